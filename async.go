@@ -1,36 +1,21 @@
 package logger
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"log"
-	"os"
-	"strings"
-	"time"
 )
 
 type LoggerAsync struct {
-	ch              chan string
-	chRaw           chan string
-	tag             string
-	enDebug         bool
-	infoStyle       []int8
-	warnStyle       []int8
-	errorStyle      []int8
-	debugStyle      []int8
-	panicStyle      []int8
-	fatalStyle      []int8
-	writeFileEnable bool
-	objectName      string
-	file            *os.File
-	fileName        string
-	path            string
+	*core
+	queue  *queue
+	fields []Field
 }
 
 // New creates a new Logger instance
 // tag: a string that will be displayed in the log message, max 7 characters
-// bufferSize: the size of the buffered channel
-// debugMode: if true, debug messages will be displayed
+// bufferSize: the size of the buffered channel, every message passes through
+// it once and a single goroutine writes it to the file and all sinks in order
+// debugMode: if true, debug messages will be displayed, see SetLevel
 // returns a pointer to the Logger instance
 // Example:
 // logger := logger.New("GPIO", 100, true)
@@ -38,240 +23,171 @@ type LoggerAsync struct {
 // logger.Info("GPIO handler started")
 // log format: [INFO] [TIME] [GPIO]: GPIO handler started
 func NewAsync(tag string, bufferSize int, debugMode bool) *LoggerAsync {
-	lenTag := len(tag)
-	if lenTag < 7 {
-		tag += strings.Repeat(" ", 7-lenTag)
-	}
-	tag = "[" + tag + "]"
-
 	logger := &LoggerAsync{
-		ch:              make(chan string, bufferSize), // Buffered channel
-		chRaw:           make(chan string, bufferSize), // Buffered channel
-		tag:             tag,
-		enDebug:         debugMode,
-		writeFileEnable: false,
+		core:  newCore(tag, debugMode),
+		queue: newQueue(bufferSize), // Buffered channel
 	}
 	logger.init()
 
 	return logger
 }
 
-// Async logging function that runs in a separate goroutine
-func (l *LoggerAsync) init() {
-	log.SetOutput(os.Stdout)
-	log.SetFlags(0) // Disable the default timestamp and log prefix
-	go func() {
-		for logMsg := range l.ch {
-			log.Print(logMsg)
-		}
-	}()
-
-	go func() {
-		for logMsgRaw := range l.chRaw {
-			l.writeLog(logMsgRaw)
-		}
-	}()
-}
-
-func (l *LoggerAsync) ChangeFileRoutine(hour int, minute int) error {
-	if !l.writeFileEnable {
-		return errors.New("set write files enable first")
+// With returns a child logger that appends the given key/value pairs to every
+// line it writes, the child shares styles, files and the queue with its parent
+func (l *LoggerAsync) With(keyvals ...any) Logger {
+	return &LoggerAsync{
+		core:   l.core,
+		queue:  l.queue,
+		fields: appendFields(l.fields, keyvals...),
 	}
-	HOUR := hour
-	MINUTE := minute
-	go func() {
-		for range time.Tick(1 * time.Minute) {
-			hours, minutes, _ := time.Now().Clock()
-			if hours == HOUR && minutes == MINUTE {
-				// Close first previous object file
-				l.file.Close()
-
-				// Create new file object with the append mode
-				l.fileName = fileNameGenerator(l.objectName)
-				l.file = createAndAppendObject(l.fileName, l.path)
-			}
-		}
-	}()
-	return nil
 }
 
-func (l *LoggerAsync) writeLog(msg string) {
-	if l.writeFileEnable {
-		l.file.WriteString(msg + "\n")
-	}
+func (l *LoggerAsync) loggerFields() []Field {
+	return l.fields
 }
 
-func (l *LoggerAsync) SetWriteFilesEnable(path string, objectName string) {
-	// Initial file object
-	l.objectName = objectName
-	l.path = newFolderPath(path)
-	l.fileName = fileNameGenerator(l.objectName)
-	l.file = createAndAppendObject(l.fileName, path)
-	l.writeFileEnable = true
-}
-
-func (l *LoggerAsync) applyStyle(str string, styles ...int8) string {
-	resStr := "\033["
-
-	for i := range len(styles) {
-		if i == 0 {
-			resStr += fmt.Sprintf("%d", styles[i])
-		} else {
-			resStr += fmt.Sprintf(";%d", styles[i])
-		}
+// Async logging function that runs in a separate goroutine, it is the only
+// writer so the file and every sink see the messages in the same order
+func (l *LoggerAsync) init() {
+	go l.queue.drain(l.core, l.dispatch)
+}
+
+// Close stops accepting messages, waits until the writer goroutine wrote
+// everything queued before the call, then commits and closes the log file and
+// waits for the compression of rotated files. It can be called more than once.
+// When ctx is done before the writer or the compression finishes, the file is
+// closed anyway and ctx.Err() is returned. Messages logged after Close go to
+// the fallback writer, if one is set.
+func (l *LoggerAsync) Close(ctx context.Context) error {
+	l.shutdown()
+	// queue.close waits for the pushes in flight, it must not hold up Close
+	// past the deadline of ctx
+	go l.queue.close()
+
+	var err error
+	select {
+	case <-l.queue.done:
+	case <-ctx.Done():
+		err = ctx.Err()
 	}
-	resStr += fmt.Sprintf("m%s\033[0m", str)
-
-	return resStr
-}
-
-func (l *LoggerAsync) getTime() string {
-	return "[" + time.Now().Format("2006-01-02 15:04:05.000") + "] "
-}
-
-func (l *LoggerAsync) Flush() {
-	close(l.ch)
-	close(l.chRaw)
-	for logMsg := range l.ch {
-		log.Print(logMsg)
+	if cerr := l.file.close(); err == nil {
+		err = cerr
 	}
-	for logMsgRaw := range l.chRaw {
-		log.Print(logMsgRaw)
+	if werr := l.file.waitCompress(ctx); err == nil {
+		err = werr
 	}
+	return err
 }
 
-func (l *LoggerAsync) SetInfoStyle(styles ...int8) {
-	for i := range len(styles) {
-		l.infoStyle = append(l.infoStyle, styles[i])
+// Sync blocks until every message logged before the call was written to the
+// file and all sinks, then commits the log file to stable storage. Unlike
+// Close the logger stays usable afterwards.
+func (l *LoggerAsync) Sync() error {
+	done := make(chan struct{})
+	if !l.queue.pushBarrier(done) {
+		return errClosed
 	}
+	<-done
+	return l.file.sync()
 }
 
-func (l *LoggerAsync) SetWarnStyle(styles ...int8) {
-	for i := range len(styles) {
-		l.warnStyle = append(l.warnStyle, styles[i])
-	}
+// Flush waits for every queued message and closes the logger
+//
+// Deprecated: Flush closes the logger, use Close instead.
+func (l *LoggerAsync) Flush() {
+	l.Close(context.Background())
 }
 
-func (l *LoggerAsync) SetErrorStyle(styles ...int8) {
-	for i := range len(styles) {
-		l.errorStyle = append(l.errorStyle, styles[i])
-	}
+func (l *LoggerAsync) log(lv Level, msg string, args []any) {
+	e := l.newEntry(lv, msg, l.fields)
+	// Skip log and the exported method calling it
+	e.Caller = l.caller(2)
+	e.Stack = l.stack(lv, 2, args, l.fields)
+	l.logEntry(e)
 }
 
-func (l *LoggerAsync) SetDebugStyle(styles ...int8) {
-	for i := range len(styles) {
-		l.debugStyle = append(l.debugStyle, styles[i])
+func (l *LoggerAsync) logEntry(e *Entry) {
+	if l.closed.Load() || !l.queue.push(e) {
+		l.writeFallback(e)
+		releaseEntry(e)
 	}
 }
 
-func (l *LoggerAsync) SetPanicStyle(styles ...int8) {
-	for i := range len(styles) {
-		l.panicStyle = append(l.panicStyle, styles[i])
-	}
-}
+// LOG FORMAT
+// [TIME] [INFO ] [TAG]: message key=value
 
-func (l *LoggerAsync) SetFatalStyle(styles ...int8) {
-	for i := range len(styles) {
-		l.fatalStyle = append(l.fatalStyle, styles[i])
+func (l *LoggerAsync) Trace(a ...any) {
+	if l.enabled(LevelTrace) {
+		l.log(LevelTrace, message(a), a)
 	}
 }
 
-func (l *LoggerAsync) SetDefaultStyle() {
-	l.SetInfoStyle(StyleFgWhite)
-	l.SetWarnStyle(StyleFgYellow)
-	l.SetErrorStyle(StyleFgRed)
-	l.SetDebugStyle(StyleFontItalic, StyleFontComment)
-	l.SetPanicStyle(StyleFontBold, StyleFgBlack, StyleBgMagenta)
-	l.SetFatalStyle(StyleFontBold, StyleFgBlack, StyleBgRed)
+func (l *LoggerAsync) Tracef(format string, a ...any) {
+	if l.enabled(LevelTrace) {
+		l.log(LevelTrace, fmt.Sprintf(format, a...), a)
+	}
 }
 
-// LOG FORMAT
-// [INFO] [TIME] [TAG]: message
-
 func (l *LoggerAsync) Info(a ...any) {
-	msg := l.getTime() + infoKey + l.tag + ": " + fmt.Sprint(a...)
-	l.chRaw <- msg
-	msg = l.applyStyle(msg, l.infoStyle...)
-	l.ch <- msg
+	if l.enabled(LevelInfo) {
+		l.log(LevelInfo, message(a), a)
+	}
 }
 
 func (l *LoggerAsync) Infof(format string, a ...any) {
-	msg := l.getTime() + infoKey + l.tag + ": " + fmt.Sprintf(format, a...)
-	l.chRaw <- msg
-	msg = l.applyStyle(msg, l.infoStyle...)
-	l.ch <- msg
+	if l.enabled(LevelInfo) {
+		l.log(LevelInfo, fmt.Sprintf(format, a...), a)
+	}
 }
 
 func (l *LoggerAsync) Warn(a ...any) {
-	msg := l.getTime() + warnKey + l.tag + ": " + fmt.Sprint(a...)
-	l.chRaw <- msg
-	msg = l.applyStyle(msg, l.warnStyle...)
-	l.ch <- msg
+	if l.enabled(LevelWarn) {
+		l.log(LevelWarn, message(a), a)
+	}
 }
 
 func (l *LoggerAsync) Warnf(format string, a ...any) {
-	msg := l.getTime() + warnKey + l.tag + ": " + fmt.Sprintf(format, a...)
-	l.chRaw <- msg
-	msg = l.applyStyle(msg, l.warnStyle...)
-	l.ch <- msg
+	if l.enabled(LevelWarn) {
+		l.log(LevelWarn, fmt.Sprintf(format, a...), a)
+	}
 }
 
 func (l *LoggerAsync) Error(a ...any) {
-	msg := l.getTime() + errorKey + l.tag + ": " + fmt.Sprint(a...)
-	l.chRaw <- msg
-	msg = l.applyStyle(msg, l.errorStyle...)
-	l.ch <- msg
+	if l.enabled(LevelError) {
+		l.log(LevelError, message(a), a)
+	}
 }
 
 func (l *LoggerAsync) Errorf(format string, a ...any) {
-	msg := l.getTime() + errorKey + l.tag + ": " + fmt.Sprintf(format, a...)
-	l.chRaw <- msg
-	msg = l.applyStyle(msg, l.errorStyle...)
-	l.ch <- msg
+	if l.enabled(LevelError) {
+		l.log(LevelError, fmt.Sprintf(format, a...), a)
+	}
 }
 
 func (l *LoggerAsync) Debug(a ...any) {
-	if l.enDebug {
-		msg := l.getTime() + debugKey + l.tag + ": " + fmt.Sprint(a...)
-		l.chRaw <- msg
-		msg = l.applyStyle(msg, l.debugStyle...)
-		l.ch <- msg
+	if l.enabled(LevelDebug) {
+		l.log(LevelDebug, message(a), a)
 	}
 }
 
 func (l *LoggerAsync) Debugf(format string, a ...any) {
-	if l.enDebug {
-		msg := l.getTime() + debugKey + l.tag + ": " + fmt.Sprintf(format, a...)
-		l.chRaw <- msg
-		msg = l.applyStyle(msg, l.debugStyle...)
-		l.ch <- msg
+	if l.enabled(LevelDebug) {
+		l.log(LevelDebug, fmt.Sprintf(format, a...), a)
 	}
 }
 
 func (l *LoggerAsync) Panic(a ...any) {
-	msg := l.getTime() + panicKey + l.tag + ": " + fmt.Sprint(a...)
-	l.chRaw <- msg
-	msg = l.applyStyle(msg, l.panicStyle...)
-	l.ch <- msg
+	l.log(LevelPanic, message(a), a)
 }
 
 func (l *LoggerAsync) Panicf(format string, a ...any) {
-	msg := l.getTime() + panicKey + l.tag + ": " + fmt.Sprintf(format, a...)
-	l.chRaw <- msg
-	msg = l.applyStyle(msg, l.panicStyle...)
-	l.ch <- msg
+	l.log(LevelPanic, fmt.Sprintf(format, a...), a)
 }
 
 func (l *LoggerAsync) Fatal(a ...any) {
-	msg := l.getTime() + fatalKey + l.tag + ": " + fmt.Sprint(a...)
-	l.chRaw <- msg
-	msg = l.applyStyle(msg, l.fatalStyle...)
-	l.ch <- msg
+	l.log(LevelFatal, message(a), a)
 }
 
 func (l *LoggerAsync) Fatalf(format string, a ...any) {
-	msg := l.getTime() + fatalKey + l.tag + ": " + fmt.Sprintf(format, a...)
-	l.chRaw <- msg
-	msg = l.applyStyle(msg, l.fatalStyle...)
-	l.ch <- msg
+	l.log(LevelFatal, fmt.Sprintf(format, a...), a)
 }
@@ -0,0 +1,38 @@
+package logger
+
+import "time"
+
+// BackpressureMode selects what LoggerAsync does when its buffer is full
+type BackpressureMode int32
+
+const (
+	// BackpressureBlock waits until there is room in the buffer
+	BackpressureBlock BackpressureMode = iota
+	// BackpressureDropNewest discards the message being logged
+	BackpressureDropNewest
+	// BackpressureDropOldest discards the oldest buffered message to make room,
+	// without a buffer there is nothing older so the new message is discarded
+	BackpressureDropOldest
+	// BackpressureTimeout waits up to Timeout, then discards the message
+	BackpressureTimeout
+)
+
+// BackpressurePolicy configures LoggerAsync for a full buffer, the zero value
+// blocks like an unbuffered logger would
+type BackpressurePolicy struct {
+	Mode    BackpressureMode
+	Timeout time.Duration
+}
+
+// SetBackpressure selects what happens when the buffer is full, by default
+// logging blocks until the writer goroutines catch up
+// Example:
+// logger.SetBackpressure(logger.BackpressurePolicy{Mode: logger.BackpressureDropOldest})
+func (l *LoggerAsync) SetBackpressure(p BackpressurePolicy) {
+	l.queue.setPolicy(p)
+}
+
+// Dropped returns how many messages the backpressure policy discarded so far
+func (l *LoggerAsync) Dropped() uint64 {
+	return l.queue.dropped.Load()
+}
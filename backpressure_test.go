@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stallWriter blocks the first Write until release is closed, like a console
+// piped to a slow consumer
+type stallWriter struct {
+	testBuffer
+	once    sync.Once
+	entered chan struct{}
+	release chan struct{}
+}
+
+func newStallWriter() *stallWriter {
+	return &stallWriter{entered: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (w *stallWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() {
+		close(w.entered)
+		<-w.release
+	})
+	return w.testBuffer.Write(p)
+}
+
+// stalledLogger returns an async logger with a buffer of 2 whose console
+// writer is stuck writing "message 0"
+func stalledLogger(t *testing.T, p BackpressurePolicy) (*LoggerAsync, *stallWriter) {
+	t.Helper()
+	logger := NewAsync("TEST", 2, false)
+	w := newStallWriter()
+	logger.SetOutput(w)
+	logger.SetBackpressure(p)
+
+	logger.Info("message 0")
+	select {
+	case <-w.entered:
+	case <-time.After(time.Second):
+		t.Fatal("Writer goroutine never started")
+	}
+	return logger, w
+}
+
+func TestBackpressure_DropNewest(t *testing.T) {
+	logger, w := stalledLogger(t, BackpressurePolicy{Mode: BackpressureDropNewest})
+	for i := 1; i < 10; i++ {
+		logger.Infof("message %d", i)
+	}
+
+	if got := logger.Dropped(); got != 7 {
+		t.Errorf("Expected 7 dropped messages but got %d", got)
+	}
+
+	close(w.release)
+	output := w.waitLines(4)
+	for _, want := range []string{"message 0", "message 1", "message 2", "7 messages dropped"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in: %q", want, output)
+		}
+	}
+	if strings.Contains(output, "message 3") {
+		t.Errorf("Expected newer messages to be dropped but got: %q", output)
+	}
+}
+
+func TestBackpressure_DropOldest(t *testing.T) {
+	logger, w := stalledLogger(t, BackpressurePolicy{Mode: BackpressureDropOldest})
+	for i := 1; i < 10; i++ {
+		logger.Infof("message %d", i)
+	}
+
+	if got := logger.Dropped(); got != 7 {
+		t.Errorf("Expected 7 dropped messages but got %d", got)
+	}
+
+	close(w.release)
+	output := w.waitLines(4)
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	want := []string{"message 0", "message 8", "message 9", "7 messages dropped"}
+	for i := range want {
+		if i >= len(lines) || !strings.Contains(lines[i], want[i]) {
+			t.Errorf("Expected line %d to contain %q in: %q", i, want[i], output)
+		}
+	}
+}
+
+func TestBackpressure_DropOldest_Unbuffered(t *testing.T) {
+	logger := NewAsync("TEST", 0, false)
+	w := newStallWriter()
+	logger.SetOutput(w)
+
+	// Blocks until the writer goroutine takes it
+	logger.Info("message 0")
+	select {
+	case <-w.entered:
+	case <-time.After(time.Second):
+		t.Fatal("Writer goroutine never started")
+	}
+	logger.SetBackpressure(BackpressurePolicy{Mode: BackpressureDropOldest})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info("message 1")
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Info not to wait for the stalled writer")
+	}
+	if got := logger.Dropped(); got != 1 {
+		t.Errorf("Expected one dropped message but got %d", got)
+	}
+	close(w.release)
+}
+
+func TestBackpressure_Timeout(t *testing.T) {
+	logger, w := stalledLogger(t, BackpressurePolicy{Mode: BackpressureTimeout, Timeout: 10 * time.Millisecond})
+	logger.Info("message 1")
+	logger.Info("message 2")
+
+	start := time.Now()
+	logger.Info("message 3")
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("Expected the call to wait for the timeout but it took %v", elapsed)
+	}
+
+	if got := logger.Dropped(); got != 1 {
+		t.Errorf("Expected one dropped message but got %d", got)
+	}
+
+	close(w.release)
+	if output := w.waitLines(4); !strings.Contains(output, "1 messages dropped") {
+		t.Errorf("Expected a dropped report but got: %q", output)
+	}
+}
+
+func TestBackpressure_NewOptions(t *testing.T) {
+	l := New(Options{Tag: "TEST", Async: true, BufferSize: 1, Backpressure: BackpressurePolicy{Mode: BackpressureDropNewest}})
+	w := newStallWriter()
+	l.SetOutput(w)
+
+	for i := range 100 {
+		l.Info(fmt.Sprint("message ", i))
+	}
+	close(w.release)
+
+	if got := l.(*LoggerAsync).Dropped(); got == 0 {
+		t.Errorf("Expected dropped messages with a stalled writer")
+	}
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoggerAsync_Sync(t *testing.T) {
+	dir := t.TempDir()
+	logger := NewAsync("TEST", 10, false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetWriteFilesEnable(dir, "OBJ")
+
+	for round := 1; round <= 2; round++ {
+		for i := range 100 {
+			logger.Infof("round %d message %d", round, i)
+		}
+		if err := logger.Sync(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got := strings.Count(buf.String(), "\n"); got != 100*round {
+			t.Errorf("Expected %d console lines after Sync but got %d", 100*round, got)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, fileNameGenerator("OBJ")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Count(string(data), "\n"); got != 100*round {
+			t.Errorf("Expected %d file lines after Sync but got %d", 100*round, got)
+		}
+	}
+}
+
+func TestLoggerAsync_Sync_AfterClose(t *testing.T) {
+	logger := NewAsync("TEST", 10, false)
+	logger.SetOutput(&testBuffer{})
+	logger.Close(context.Background())
+
+	if err := logger.Sync(); !errors.Is(err, errClosed) {
+		t.Errorf("Expected Sync after Close to fail but got: %v", err)
+	}
+}
+
+func TestLoggerAsync_Sync_DropOldest(t *testing.T) {
+	logger, w := stalledLogger(t, BackpressurePolicy{Mode: BackpressureDropOldest})
+
+	synced := make(chan error)
+	go func() {
+		synced <- logger.Sync()
+	}()
+	// Give Sync the time to queue its barrier, the messages below push it
+	// out of the full buffer
+	time.Sleep(10 * time.Millisecond)
+	for i := 1; i < 10; i++ {
+		logger.Infof("message %d", i)
+	}
+	close(w.release)
+
+	select {
+	case err := <-synced:
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Sync to return once the writer caught up")
+	}
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func benchmarkFile(b *testing.B, l Logger, buffering FileBuffering) {
+	l.SetOutput(io.Discard)
+	l.SetWriteFilesEnable(b.TempDir(), "BENCH")
+	l.SetFileBuffering(buffering)
+	defer l.Close(context.Background())
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info("benchmark message ", i)
+	}
+	l.Sync()
+}
+
+func BenchmarkLoggerSync_File_Unbuffered(b *testing.B) {
+	benchmarkFile(b, NewSync("BENCH", false), FileBuffering{})
+}
+
+func BenchmarkLoggerSync_File_Buffered(b *testing.B) {
+	benchmarkFile(b, NewSync("BENCH", false), FileBuffering{Size: 64 << 10})
+}
+
+func BenchmarkLoggerAsync_File_Unbuffered(b *testing.B) {
+	benchmarkFile(b, NewAsync("BENCH", 1024, false), FileBuffering{})
+}
+
+func BenchmarkLoggerAsync_File_Buffered(b *testing.B) {
+	benchmarkFile(b, NewAsync("BENCH", 1024, false), FileBuffering{Size: 64 << 10})
+}
+
+func BenchmarkLoggerSync_Info(b *testing.B) {
+	l := NewSync("BENCH", false)
+	l.SetOutput(io.Discard)
+	l.SetDefaultStyle()
+	b.ReportAllocs()
+	for b.Loop() {
+		l.Info("benchmark message")
+	}
+}
+
+func BenchmarkLoggerSync_Infof(b *testing.B) {
+	l := NewSync("BENCH", false)
+	l.SetOutput(io.Discard)
+	l.SetDefaultStyle()
+	b.ReportAllocs()
+	for b.Loop() {
+		l.Infof("benchmark message %d", 42)
+	}
+}
+
+func BenchmarkLoggerAsync_Info(b *testing.B) {
+	l := NewAsync("BENCH", 1024, false)
+	l.SetOutput(io.Discard)
+	l.SetDefaultStyle()
+	defer l.Close(context.Background())
+	b.ReportAllocs()
+	for b.Loop() {
+		l.Info("benchmark message")
+	}
+}
+
+func BenchmarkLoggerAsync_Infof(b *testing.B) {
+	l := NewAsync("BENCH", 1024, false)
+	l.SetOutput(io.Discard)
+	l.SetDefaultStyle()
+	defer l.Close(context.Background())
+	b.ReportAllocs()
+	for b.Loop() {
+		l.Infof("benchmark message %d", 42)
+	}
+}
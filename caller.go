@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// CallerMode selects how the call site of a log message is shown
+type CallerMode int32
+
+const (
+	// CallerOff does not record the call site
+	CallerOff CallerMode = iota
+	// CallerShort records the file with its parent directory and the
+	// function without the import path, e.g. gpio/pin.go:42 gpio.(*Pin).Set
+	CallerShort
+	// CallerFull records the absolute file path and the full function name
+	CallerFull
+)
+
+// Caller is the call site of a log message, the zero value means no caller
+// was recorded
+type Caller struct {
+	File     string
+	Line     int
+	Function string
+}
+
+// IsZero reports whether no caller was recorded
+func (c Caller) IsZero() bool {
+	return c.File == ""
+}
+
+// String returns file:line
+func (c Caller) String() string {
+	return c.File + ":" + strconv.Itoa(c.Line)
+}
+
+// SetCaller enables caller annotation, functions that wrap the logger can
+// call Helper so the caller reported is the one calling them instead
+// Example:
+// logger.SetCaller(logger.CallerShort)
+// log format: [TIME] [INFO ] [TAG    ] main/main.go:21 main.run: message
+func (l *core) SetCaller(mode CallerMode) {
+	l.callerMode.Store(int32(mode))
+}
+
+// Helper marks the calling function as a logging helper, like
+// testing.T.Helper, so caller annotation reports the line calling the helper
+func (l *core) Helper() {
+	pc, _, _, ok := runtime.Caller(1)
+	if !ok {
+		return
+	}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		l.helpers.Store(fn.Name(), struct{}{})
+	}
+}
+
+// caller returns the call site skip frames above the function calling it,
+// skipping every function marked with Helper
+func (l *core) caller(skip int) Caller {
+	mode := CallerMode(l.callerMode.Load())
+	if mode == CallerOff {
+		return Caller{}
+	}
+
+	var pcs [32]uintptr
+	// +2 skips runtime.Callers and this function
+	n := runtime.Callers(skip+2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if _, helper := l.helpers.Load(frame.Function); !helper || !more {
+			return makeCaller(frame, mode)
+		}
+	}
+}
+
+// callerFromPC resolves a program counter such as slog.Record.PC
+func (l *core) callerFromPC(pc uintptr) Caller {
+	mode := CallerMode(l.callerMode.Load())
+	if mode == CallerOff || pc == 0 {
+		return Caller{}
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return makeCaller(frame, mode)
+}
+
+func makeCaller(frame runtime.Frame, mode CallerMode) Caller {
+	c := Caller{File: frame.File, Line: frame.Line, Function: frame.Function}
+	if mode == CallerShort {
+		c.File = shortFile(c.File)
+		c.Function = c.Function[strings.LastIndexByte(c.Function, '/')+1:]
+	}
+	return c
+}
+
+// shortFile keeps the last directory and the file name
+func shortFile(file string) string {
+	i := strings.LastIndexByte(file, '/')
+	if i < 0 {
+		return file
+	}
+	if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+		return file[j+1:]
+	}
+	return file
+}
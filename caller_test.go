@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// currentLine returns the line of the statement calling it
+func currentLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func logWarning(l Logger, msg string) {
+	l.Helper()
+	l.Warn(msg)
+}
+
+func TestLoggerSync_Caller_Short(t *testing.T) {
+	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetCaller(CallerShort)
+
+	line := currentLine() + 1
+	logger.Infof("with %s", "caller")
+
+	want := fmt.Sprintf("/caller_test.go:%d go-logger.TestLoggerSync_Caller_Short: with caller", line)
+	if output := buf.waitLines(1); !strings.Contains(output, want) {
+		t.Errorf("Expected %q in: %q", want, output)
+	}
+}
+
+func TestLoggerAsync_Caller_Full(t *testing.T) {
+	logger := NewAsync("TEST", 10, false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetCaller(CallerFull)
+	logger.SetConsoleEncoder(NewLogfmtEncoder())
+
+	_, file, line, _ := runtime.Caller(0)
+	logger.With("k", "v").Error("full caller")
+
+	got := parseLogfmt(t, strings.TrimSpace(buf.waitLines(1)))
+	if got["caller"] != fmt.Sprintf("%s:%d", file, line+1) {
+		t.Errorf("Unexpected caller %q", got["caller"])
+	}
+	if got["func"] != "github.com/ABA-Developer/go-logger.TestLoggerAsync_Caller_Full" {
+		t.Errorf("Unexpected function %q", got["func"])
+	}
+}
+
+func TestLoggerSync_Caller_Helper(t *testing.T) {
+	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetCaller(CallerShort)
+	child := logger.With("child", 1)
+
+	line := currentLine() + 1
+	logWarning(child, "from helper")
+
+	want := fmt.Sprintf("/caller_test.go:%d go-logger.TestLoggerSync_Caller_Helper: from helper", line)
+	if output := buf.waitLines(1); !strings.Contains(output, want) {
+		t.Errorf("Expected %q in: %q", want, output)
+	}
+}
+
+func TestSlogHandler_Caller(t *testing.T) {
+	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetCaller(CallerShort)
+
+	line := currentLine() + 1
+	slog.New(NewSlogHandler(logger)).Info("from slog")
+
+	want := fmt.Sprintf("/caller_test.go:%d go-logger.TestSlogHandler_Caller: from slog", line)
+	if output := buf.waitLines(1); !strings.Contains(output, want) {
+		t.Errorf("Expected %q in: %q", want, output)
+	}
+}
+
+func TestLoggerSync_Caller_Off(t *testing.T) {
+	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+
+	logger.Info("no caller")
+
+	if output := buf.waitLines(1); !strings.Contains(output, "[TEST   ]: no caller") {
+		t.Errorf("Expected no caller but got: %q", output)
+	}
+}
@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoggerAsync_Close(t *testing.T) {
+	dir := t.TempDir()
+	logger := NewAsync("TEST", 10, false)
+	buf, fallback := &testBuffer{}, &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetFallbackWriter(fallback)
+	logger.SetWriteFilesEnable(dir, "OBJ")
+
+	for i := range 50 {
+		logger.Infof("message %d", i)
+	}
+
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := strings.Count(buf.String(), "\n"); got != 50 {
+		t.Errorf("Expected 50 console lines after Close but got %d", got)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, fileNameGenerator("OBJ")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), "\n"); got != 50 {
+		t.Errorf("Expected 50 file lines after Close but got %d", got)
+	}
+
+	if err := logger.Close(context.Background()); err != nil {
+		t.Errorf("Expected a second Close to succeed but got: %v", err)
+	}
+
+	logger.With("late", true).Warn("after close")
+	if output := fallback.String(); !strings.Contains(output, "after close late=true") || strings.Contains(output, "\033[") {
+		t.Errorf("Expected a plain line in the fallback writer but got: %q", output)
+	}
+	if strings.Contains(buf.String(), "after close") {
+		t.Errorf("Expected nothing on the console after Close")
+	}
+}
+
+func TestLoggerAsync_Close_Silent(t *testing.T) {
+	logger := NewAsync("TEST", 10, false)
+	logger.SetOutput(&testBuffer{})
+	logger.Close(context.Background())
+
+	// Must neither panic nor block
+	logger.Info("dropped")
+	logger.Flush()
+}
+
+func TestLoggerAsync_Close_Deadline(t *testing.T) {
+	logger := NewAsync("TEST", 1, false)
+	w := newStallWriter()
+	logger.SetOutput(w)
+	defer close(w.release)
+
+	logger.Info("message 0")
+	<-w.entered
+	logger.Info("message 1")
+
+	blocked := make(chan struct{})
+	go func() {
+		logger.Info("message 2")
+		close(blocked)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := logger.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline to be exceeded but got: %v", err)
+	}
+
+	select {
+	case <-blocked:
+	case <-time.After(time.Second):
+		t.Errorf("Expected Close to release a blocked log call")
+	}
+}
+
+func TestLoggerAsync_Close_DeadlinePushInFlight(t *testing.T) {
+	logger := NewAsync("TEST", 1, false)
+	logger.SetOutput(&testBuffer{})
+
+	// A push that does not return, queue.close waits for it
+	logger.queue.mu.RLock()
+	defer logger.queue.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	closed := make(chan error, 1)
+	go func() {
+		closed <- logger.Close(ctx)
+	}()
+
+	select {
+	case err := <-closed:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the deadline to be exceeded but got: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Close to return at the deadline")
+	}
+}
+
+func TestLoggerSync_Close(t *testing.T) {
+	dir := t.TempDir()
+	logger := NewSync("TEST", false)
+	fallback := &testBuffer{}
+	logger.SetOutput(&testBuffer{})
+	logger.SetFallbackWriter(fallback)
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	logger.Info("before close")
+
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := logger.Close(context.Background()); err != nil {
+		t.Errorf("Expected a second Close to succeed but got: %v", err)
+	}
+
+	logger.Info("after close")
+	data, _ := os.ReadFile(filepath.Join(dir, fileNameGenerator("OBJ")))
+	if !strings.Contains(string(data), "before close") || strings.Contains(string(data), "after close") {
+		t.Errorf("Unexpected file content: %q", data)
+	}
+	if !strings.Contains(fallback.String(), "after close") {
+		t.Errorf("Expected the late message in the fallback writer but got: %q", fallback.String())
+	}
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"io"
+	"os"
+)
+
+// ColorMode selects whether console output is styled with ANSI escapes
+type ColorMode int32
+
+const (
+	// ColorAuto styles output written to a terminal, NO_COLOR turns styles
+	// off and FORCE_COLOR turns them on regardless of the writer
+	ColorAuto ColorMode = iota
+	// ColorAlways styles every console writer
+	ColorAlways
+	// ColorNever writes plain console output
+	ColorNever
+)
+
+// SetColorMode selects whether console output is styled, the default
+// ColorAuto only styles terminals so piped output and systemd journals stay
+// free of escape sequences
+// Example:
+// logger.SetColorMode(logger.ColorNever)
+func (l *core) SetColorMode(mode ColorMode) {
+	l.colorMode.Store(int32(mode))
+}
+
+// colored reports whether output to a writer for which ColorAuto decided auto
+// is styled
+func (l *core) colored(auto bool) bool {
+	switch ColorMode(l.colorMode.Load()) {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	return auto
+}
+
+// autoColor reports whether ColorAuto styles output written to w, following
+// the NO_COLOR and FORCE_COLOR conventions
+func autoColor(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if v := os.Getenv("FORCE_COLOR"); v != "" {
+		return v != "0" && v != "false"
+	}
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a character device such as a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAutoColor_Env(t *testing.T) {
+	tests := []struct {
+		noColor, forceColor string
+		want                bool
+	}{
+		{"", "", false},
+		{"", "1", true},
+		{"", "0", false},
+		{"1", "1", false},
+	}
+	for _, tt := range tests {
+		t.Setenv("NO_COLOR", tt.noColor)
+		t.Setenv("FORCE_COLOR", tt.forceColor)
+		if got := autoColor(&testBuffer{}); got != tt.want {
+			t.Errorf("NO_COLOR=%q FORCE_COLOR=%q: Expected %v but got %v", tt.noColor, tt.forceColor, tt.want, got)
+		}
+	}
+}
+
+func TestIsTerminal_File(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if isTerminal(f) {
+		t.Error("Expected a regular file not to be a terminal")
+	}
+}
+
+func TestLoggerSync_ColorMode(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "1")
+
+	buf := &testBuffer{}
+	logger := New(Options{Tag: "TEST", Color: ColorNever})
+	logger.SetOutput(buf)
+	logger.SetDefaultStyle()
+	logger.Info("plain")
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("Expected ColorNever to win over FORCE_COLOR but got: %q", buf.String())
+	}
+
+	buf = &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAuto)
+	logger.Info("forced")
+	if !strings.HasPrefix(buf.String(), "\033[37m") {
+		t.Errorf("Expected FORCE_COLOR to style a buffer but got: %q", buf.String())
+	}
+}
+
+func TestLoggerSync_NoStyle_NoEscapes(t *testing.T) {
+	buf := &testBuffer{}
+	logger := NewSync("TEST", false)
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
+
+	logger.Info("unstyled")
+
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("Expected no escapes without a style but got: %q", buf.String())
+	}
+}
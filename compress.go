@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SetCompress gzips log files in a background goroutine once they are no
+// longer written, i.e. after ChangeFileRoutine switches to a new day. The
+// original is removed only after the archive was read back and verified.
+// Leftovers from a crash are picked up when compression is enabled: a half
+// written .gz is redone and an uncompressed old file is compressed.
+func (l *core) SetCompress(enable bool) {
+	l.file.mu.Lock()
+	defer l.file.mu.Unlock()
+	l.file.compress = enable
+	if enable && l.file.enabled {
+		l.file.compressInBackground()
+	}
+}
+
+// compressInBackground starts compressPending without blocking the caller,
+// the caller holds f.mu so no compression starts once close disabled the file
+func (f *logFile) compressInBackground() {
+	f.compressWG.Add(1)
+	go func() {
+		defer f.compressWG.Done()
+		f.compressPending()
+	}()
+}
+
+// waitCompress blocks until the background compressions finished or ctx is
+// done, call it after close so no new ones start
+func (f *logFile) waitCompress(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		f.compressWG.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// compressPending compresses every file of this object except the one that
+// is currently written and its size rotated backups
+func (f *logFile) compressPending() {
+	f.compressMu.Lock()
+	defer f.compressMu.Unlock()
+
+	f.mu.Lock()
+	path, objectName := f.path, f.objectName
+	current := strings.TrimSuffix(f.fileName, ".txt") + "."
+	f.mu.Unlock()
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return
+	}
+	pattern := logFilePattern(objectName)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".txt") || !pattern.MatchString(name) {
+			continue
+		}
+		if strings.HasPrefix(name, current) {
+			continue
+		}
+		compressFile(filepath.Join(path, name))
+	}
+}
+
+// compressFile writes src to src.gz, verifies the archive and removes src.
+// An existing src.gz is overwritten, since src still being present means the
+// previous attempt did not finish.
+func compressFile(src string) error {
+	dst := src + ".gz"
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(out)
+	gz.Name = filepath.Base(src)
+	_, err = io.Copy(gz, in)
+	if err == nil {
+		err = gz.Close()
+	}
+	if err == nil {
+		err = out.Sync()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = verifyGzip(dst, info.Size())
+	}
+	if err != nil {
+		os.Remove(dst)
+		return err
+	}
+
+	in.Close()
+	return os.Remove(src)
+}
+
+// verifyGzip reads the whole archive, which checks the gzip CRC, and compares
+// the uncompressed length with the original size
+func verifyGzip(path string, size int64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(io.Discard, gz)
+	if err != nil {
+		return err
+	}
+	if n != size {
+		return errors.New("compressed size mismatch")
+	}
+	return gz.Close()
+}
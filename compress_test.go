@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readGzip(t *testing.T, path string) string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestCompress_Recovery(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "2025-01-01:OBJ.txt"), []byte("leftover\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "2025-01-02:OBJ.txt"), []byte("interrupted\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "2025-01-02:OBJ.txt.gz"), []byte("\x1f\x8b half written"), 0644)
+	os.WriteFile(filepath.Join(dir, "2025-01-03:OTHER.txt"), []byte("not ours\n"), 0644)
+
+	logger := NewSync("TEST", false)
+	logger.SetOutput(&testBuffer{})
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	logger.SetCompress(true)
+	logger.Info("current file stays plain")
+	logger.file.compressWG.Wait()
+
+	if got := readGzip(t, filepath.Join(dir, "2025-01-01:OBJ.txt.gz")); got != "leftover\n" {
+		t.Errorf("Unexpected archive content: %q", got)
+	}
+	if got := readGzip(t, filepath.Join(dir, "2025-01-02:OBJ.txt.gz")); got != "interrupted\n" {
+		t.Errorf("Expected the half written archive to be redone but got: %q", got)
+	}
+
+	for _, name := range []string{"2025-01-01:OBJ.txt", "2025-01-02:OBJ.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed after compression", name)
+		}
+	}
+	for _, name := range []string{"2025-01-03:OTHER.txt", fileNameGenerator("OBJ")} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be left alone: %v", name, err)
+		}
+	}
+}
+
+func TestCompress_AfterDailyRotation(t *testing.T) {
+	dir := t.TempDir()
+	logger := NewSync("TEST", false)
+	logger.SetOutput(&testBuffer{})
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	logger.SetCompress(true)
+	logger.file.compressWG.Wait()
+
+	// Pretend the current file belongs to a previous day
+	logger.file.mu.Lock()
+	logger.file.file.Close()
+	os.Rename(filepath.Join(dir, logger.file.fileName), filepath.Join(dir, "2025-01-01:OBJ.txt"))
+	logger.file.fileName = "2025-01-01:OBJ.txt"
+	logger.file.file, _ = os.OpenFile(filepath.Join(dir, "2025-01-01:OBJ.txt"), os.O_APPEND|os.O_WRONLY, 0644)
+	logger.file.mu.Unlock()
+
+	logger.Info("yesterday")
+	logger.file.rotateDaily()
+	logger.Info("today")
+	logger.file.compressWG.Wait()
+
+	if got := readGzip(t, filepath.Join(dir, "2025-01-01:OBJ.txt.gz")); len(got) == 0 {
+		t.Errorf("Expected yesterday's file to be compressed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, fileNameGenerator("OBJ"))); err != nil {
+		t.Errorf("Expected today's file to exist: %v", err)
+	}
+}
+
+func TestCompress_CloseWaits(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "2025-01-01:OBJ.txt"), bytes.Repeat([]byte("old line\n"), 100000), 0644)
+
+	logger := NewAsync("TEST", 10, false)
+	logger.SetOutput(&testBuffer{})
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	logger.SetCompress(true)
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "2025-01-01:OBJ.txt")); !os.IsNotExist(err) {
+		t.Errorf("Expected Close to wait until the old file was compressed")
+	}
+	if got := readGzip(t, filepath.Join(dir, "2025-01-01:OBJ.txt.gz")); len(got) != 900000 {
+		t.Errorf("Expected a complete archive but got %d bytes", len(got))
+	}
+}
+
+func TestCompress_CloseContext(t *testing.T) {
+	logger := NewSync("TEST", false)
+	logger.SetOutput(&testBuffer{})
+
+	// A compression that never finishes
+	logger.file.compressWG.Add(1)
+	defer logger.file.compressWG.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := logger.Close(ctx); err != context.Canceled {
+		t.Errorf("Expected context.Canceled but got: %v", err)
+	}
+}
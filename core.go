@@ -0,0 +1,358 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// core holds the state shared by a logger and every child created with With,
+// so children write through the same styles and file handle as their parent
+type core struct {
+	mu             sync.RWMutex
+	sinks          []Sink
+	tag            string
+	consoleEncoder atomic.Pointer[Encoder]
+	fileEncoder    atomic.Pointer[Encoder]
+	level          atomic.Int32
+	callerMode     atomic.Int32
+	colorMode      atomic.Int32
+	markup         atomic.Bool
+	stackLevel     atomic.Int32
+	timeFormat     atomic.Pointer[timeFormat]
+	helpers        sync.Map
+	styles         atomic.Pointer[styleSet]
+	file           *logFile
+	fallback       Sink
+	closed         atomic.Bool
+	done           chan struct{}
+	doneOnce       sync.Once
+}
+
+func newCore(tag string, debugMode bool) *core {
+	l := &core{
+		tag:  tag,
+		file: &logFile{},
+		done: make(chan struct{}),
+	}
+	l.SetConsoleEncoder(NewTextEncoder())
+	l.SetFileEncoder(NewTextEncoder())
+	l.sinks = []Sink{newConsoleSink(os.Stdout, l)}
+	l.SetStackTraceLevel(LevelOff)
+	l.timeFormat.Store(&timeFormat{})
+	l.styles.Store(&styleSet{})
+	if t, ok := themeFromEnv(); ok {
+		l.SetTheme(t)
+	}
+	if debugMode {
+		l.SetLevel(LevelDebug)
+	} else {
+		l.SetLevel(LevelInfo)
+	}
+	return l
+}
+
+// SetOutput replaces the console writers, every writer receives the console
+// encoding with the level styles applied as SetColorMode decides, the
+// default is os.Stdout
+// Example:
+// var buf bytes.Buffer
+// logger.SetOutput(&buf)
+func (l *core) SetOutput(writers ...io.Writer) {
+	sinks := make([]Sink, 0, len(writers))
+	for _, w := range writers {
+		sinks = append(sinks, newConsoleSink(w, l))
+	}
+	l.mu.Lock()
+	l.sinks = sinks
+	l.mu.Unlock()
+}
+
+// AddSink adds a sink that receives every entry next to the console writers
+func (l *core) AddSink(s Sink) {
+	l.mu.Lock()
+	l.sinks = append(l.sinks, s)
+	l.mu.Unlock()
+}
+
+// SetFallbackWriter receives the messages logged after Close, as plain text
+// lines. Without a fallback writer those messages are dropped silently.
+// Example:
+// logger.SetFallbackWriter(os.Stderr)
+func (l *core) SetFallbackWriter(w io.Writer) {
+	var s Sink
+	if w != nil {
+		s = NewWriterSink(w, NewTextEncoder())
+	}
+	l.mu.Lock()
+	l.fallback = s
+	l.mu.Unlock()
+}
+
+func (l *core) writeFallback(e *Entry) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.fallback != nil {
+		l.fallback.WriteEntry(e)
+	}
+}
+
+// shutdown marks the logger closed and stops the ChangeFileRoutine goroutine
+func (l *core) shutdown() {
+	l.doneOnce.Do(func() {
+		l.closed.Store(true)
+		close(l.done)
+	})
+}
+
+// dispatch writes e to the log file and every sink
+func (l *core) dispatch(e *Entry) {
+	l.writeFileEntry(e)
+	l.writeSinks(e)
+}
+
+func (l *core) writeSinks(e *Entry) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, s := range l.sinks {
+		s.WriteEntry(e)
+	}
+}
+
+func (l *core) ChangeFileRoutine(hour int, minute int) error {
+	if !l.file.isEnabled() {
+		return errors.New("set write files enable first")
+	}
+	HOUR := hour
+	MINUTE := minute
+	go func() {
+		ticker := time.NewTicker(1 * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-l.done:
+				return
+			}
+			hours, minutes, _ := l.file.now().Clock()
+			if hours == HOUR && minutes == MINUTE {
+				l.file.rotateDaily()
+			}
+		}
+	}()
+	return nil
+}
+
+// SetMaxFileSize rotates the log file once it would grow past size bytes, the
+// full file is renamed with an index suffix, e.g. 2025-05-23:ABA11.1.txt, and
+// a new file is started. Zero disables size based rotation.
+// It can be combined with ChangeFileRoutine.
+func (l *core) SetMaxFileSize(size int64) {
+	l.file.mu.Lock()
+	l.file.maxSize = size
+	l.file.mu.Unlock()
+}
+
+// SetMaxBackups limits how many size rotated backups are kept per day, the
+// oldest ones are removed first. Zero keeps every backup.
+func (l *core) SetMaxBackups(n int) {
+	l.file.mu.Lock()
+	l.file.maxBackups = n
+	l.file.mu.Unlock()
+}
+
+func (l *core) writeFileEntry(e *Entry) {
+	l.file.writeEntry(e, *l.fileEncoder.Load())
+}
+
+func (l *core) SetWriteFilesEnable(path string, objectName string) {
+	// Initial file object
+	l.file.open(path, objectName)
+}
+
+// SetConsoleEncoder selects the format of console output, text output is
+// colored with the level styles, other encoders are written as is. It is safe
+// to call while other goroutines are logging.
+func (l *core) SetConsoleEncoder(enc Encoder) {
+	l.consoleEncoder.Store(&enc)
+}
+
+// SetFileEncoder selects the format of the lines written by SetWriteFilesEnable
+// Example:
+// logger.SetFileEncoder(logger.NewJSONEncoder())
+func (l *core) SetFileEncoder(enc Encoder) {
+	l.fileEncoder.Store(&enc)
+}
+
+// SetLevel sets the minimum level written, Panic and Fatal messages are
+// always written. It replaces the debugMode given to the constructor and is
+// safe to call while other goroutines are logging.
+func (l *core) SetLevel(lv Level) {
+	l.level.Store(int32(lv))
+}
+
+// GetLevel returns the minimum level written
+func (l *core) GetLevel() Level {
+	return Level(l.level.Load())
+}
+
+// enabled reports whether messages of level lv are written
+func (l *core) enabled(lv Level) bool {
+	return lv >= LevelPanic || int32(lv) >= l.level.Load()
+}
+
+// appendConsoleLine encodes e with the console encoder, when color is true
+// text lines are written with the styles of the level and of the line parts
+// for a terminal showing p colors
+func (l *core) appendConsoleLine(dst []byte, e *Entry, color bool, p colorProfile) []byte {
+	enc := *l.consoleEncoder.Load()
+	se, ok := enc.(styledEncoder)
+	if !color || !ok || e.Level < LevelTrace || e.Level >= LevelOff {
+		return enc.Encode(dst, e)
+	}
+	ls := &l.currentStyles().lines[e.Level][p]
+	dst = append(dst, ls.level...)
+	dst = se.encodeStyled(dst, e, ls)
+	if ls.level != "" {
+		dst = append(dst, styleReset...)
+	}
+	return dst
+}
+
+// styleSet holds the styles of every level and line part together with the
+// escape prefixes for each color profile, computed once when a style is set.
+// It is replaced as a whole so a line never sees a half applied update.
+type styleSet struct {
+	levels [LevelOff]Style
+	parts  [partCount]Style
+	// tagColors replace the tag color of the theme when SetTagColors is on,
+	// they were resolved when PinTagColor was at pinGen
+	tagColorsOn bool
+	tagColors   [profileCount]Color
+	pinGen      uint64
+	lines       [LevelOff][profileCount]lineStyle
+}
+
+// updateStyles applies update to a copy of the styles and publishes it
+func (l *core) updateStyles(update func(set *styleSet)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	set := *l.styles.Load()
+	update(&set)
+	set.resolve(l.tag)
+	l.styles.Store(&set)
+}
+
+// currentStyles returns the styles, resolving the tag colors again when a
+// color was pinned since they were resolved. It runs while the sinks are
+// locked, so the new set is published without l.mu and a concurrent
+// updateStyles wins.
+func (l *core) currentStyles() *styleSet {
+	set := l.styles.Load()
+	if !set.tagColorsOn || set.pinGen == tagPins.gen.Load() {
+		return set
+	}
+	fresh := *set
+	fresh.resolve(l.tag)
+	l.styles.CompareAndSwap(set, &fresh)
+	return &fresh
+}
+
+// resolve looks up the tag colors of tag and computes the escape prefixes
+func (set *styleSet) resolve(tag string) {
+	set.pinGen = tagPins.gen.Load()
+	for p := range profileCount {
+		set.tagColors[p] = Color{}
+		if set.tagColorsOn {
+			set.tagColors[p] = tagColor(tag, p)
+		}
+	}
+	for lv := range set.levels {
+		for p := range profileCount {
+			ls := &set.lines[lv][p]
+			ls.level = set.levels[lv].sgr(p)
+			ls.base = set.levels[lv]
+			ls.profile = p
+			for part := range partCount {
+				ls.parts[part] = set.parts[part].sgr(p)
+			}
+			if !set.tagColors[p].IsZero() {
+				ls.parts[partTag] = set.parts[partTag].Foreground(set.tagColors[p]).sgr(p)
+			}
+		}
+	}
+}
+
+// SetLevelStyle replaces the style of messages of level lv, the zero Style
+// writes them unstyled. Colors the terminal cannot show are replaced by the
+// closest one it can.
+// Example:
+// logger.SetLevelStyle(logger.LevelWarn, logger.NewStyle(logger.AttrBold).Foreground(logger.Color256(208)))
+func (l *core) SetLevelStyle(lv Level, s Style) {
+	if lv < LevelTrace || lv >= LevelOff {
+		return
+	}
+	l.updateStyles(func(set *styleSet) {
+		set.levels[lv] = s
+	})
+}
+
+// LevelStyle returns the style of messages of level lv
+func (l *core) LevelStyle(lv Level) Style {
+	if lv < LevelTrace || lv >= LevelOff {
+		return Style{}
+	}
+	return l.styles.Load().levels[lv]
+}
+
+// ResetStyles removes every level and theme style and turns tag colors off,
+// console lines are written unstyled
+func (l *core) ResetStyles() {
+	l.updateStyles(func(set *styleSet) {
+		*set = styleSet{}
+	})
+}
+
+// The Set*Style methods replace the style of a level with the given Style*
+// codes, see StyleFromCodes
+
+func (l *core) SetTraceStyle(styles ...int8) {
+	l.SetLevelStyle(LevelTrace, StyleFromCodes(styles...))
+}
+
+func (l *core) SetInfoStyle(styles ...int8) {
+	l.SetLevelStyle(LevelInfo, StyleFromCodes(styles...))
+}
+
+func (l *core) SetWarnStyle(styles ...int8) {
+	l.SetLevelStyle(LevelWarn, StyleFromCodes(styles...))
+}
+
+func (l *core) SetErrorStyle(styles ...int8) {
+	l.SetLevelStyle(LevelError, StyleFromCodes(styles...))
+}
+
+func (l *core) SetDebugStyle(styles ...int8) {
+	l.SetLevelStyle(LevelDebug, StyleFromCodes(styles...))
+}
+
+func (l *core) SetPanicStyle(styles ...int8) {
+	l.SetLevelStyle(LevelPanic, StyleFromCodes(styles...))
+}
+
+func (l *core) SetFatalStyle(styles ...int8) {
+	l.SetLevelStyle(LevelFatal, StyleFromCodes(styles...))
+}
+
+// SetDefaultStyle applies the theme selected with the LOGGER_THEME
+// environment variable, or the default theme when it is not set
+func (l *core) SetDefaultStyle() {
+	t, ok := themeFromEnv()
+	if !ok {
+		t, _ = LookupTheme("default")
+	}
+	l.SetTheme(t)
+}
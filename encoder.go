@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Entry is a single log message handed to an Encoder, the encoders leave the
+// time out when Time is zero
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Tag     string
+	Message string
+	Fields  []Field
+	Caller  Caller
+	Stack   string
+
+	// timeLayout is the layout set with SetTimeFormat when e was created
+	timeLayout string
+	// markup is whether Message is written with SetMarkup markup
+	markup bool
+}
+
+// Encoder turns an Entry into a single line of output. Encode appends the
+// line to dst without the trailing newline and returns the extended buffer.
+type Encoder interface {
+	Encode(dst []byte, e *Entry) []byte
+}
+
+// NewTextEncoder returns the default human readable encoder
+// log format: [TIME] [INFO ] [TAG    ]: message key=value
+// with caller: [TIME] [INFO ] [TAG    ] dir/file.go:12 pkg.func: message key=value
+func NewTextEncoder() Encoder {
+	return textEncoder{}
+}
+
+// NewJSONEncoder returns an encoder writing one JSON object per line, a field
+// named like one of the entry keys is written as fields.<key>
+// log format: {"time":"...","level":"info","tag":"TAG","msg":"message","key":"value"}
+func NewJSONEncoder() Encoder {
+	return jsonEncoder{}
+}
+
+type textEncoder struct{}
+
+func (enc textEncoder) Encode(dst []byte, e *Entry) []byte {
+	return enc.encodeStyled(dst, e, nil)
+}
+
+func (textEncoder) encodeStyled(dst []byte, e *Entry, ls *lineStyle) []byte {
+	if !e.Time.IsZero() {
+		dst = append(dst, '[')
+		dst = ls.open(dst, partTime)
+		dst = appendTime(dst, e, textTimeLayout)
+		dst = ls.close(dst, partTime)
+		dst = append(dst, "] "...)
+	}
+	dst = append(dst, e.Level.key()...)
+	dst = ls.open(dst, partTag)
+	dst = appendTag(dst, e.Tag)
+	dst = ls.close(dst, partTag)
+	if !e.Caller.IsZero() {
+		dst = append(dst, ' ')
+		dst = append(dst, e.Caller.File...)
+		dst = append(dst, ':')
+		dst = strconv.AppendInt(dst, int64(e.Caller.Line), 10)
+		dst = append(dst, ' ')
+		dst = append(dst, e.Caller.Function...)
+	}
+	dst = append(dst, ": "...)
+	dst = appendMessage(dst, e, ls)
+	dst = appendFieldsText(dst, e.Fields, ls)
+	if e.Stack != "" {
+		dst = appendIndented(dst, e.Stack)
+	}
+	return dst
+}
+
+type jsonEncoder struct{}
+
+func (jsonEncoder) Encode(dst []byte, e *Entry) []byte {
+	dst = append(dst, '{')
+	if !e.Time.IsZero() {
+		dst = append(dst, `"time":`...)
+		switch e.timeLayout {
+		case "":
+			dst = append(dst, '"')
+			dst = e.Time.AppendFormat(dst, structuredTimeLayout)
+			dst = append(dst, '"')
+		case TimeFormatUnixMilli:
+			dst = appendTime(dst, e, "")
+		default:
+			dst = appendJSONString(dst, e.Time.Format(e.timeLayout))
+		}
+		dst = append(dst, ',')
+	}
+	dst = append(dst, `"level":"`...)
+	dst = append(dst, e.Level.String()...)
+	dst = append(dst, `","tag":`...)
+	dst = appendJSONString(dst, e.Tag)
+	if !e.Caller.IsZero() {
+		dst = append(dst, `,"caller":`...)
+		dst = appendJSONString(dst, e.Caller.String())
+		dst = append(dst, `,"func":`...)
+		dst = appendJSONString(dst, e.Caller.Function)
+	}
+	dst = append(dst, `,"msg":`...)
+	dst = appendJSONString(dst, plainMessage(e))
+	for _, f := range e.Fields {
+		dst = append(dst, ',')
+		dst = appendJSONString(dst, structuredKey(f.Key))
+		dst = append(dst, ':')
+		dst = appendJSONValue(dst, f.Value)
+	}
+	if e.Stack != "" {
+		dst = append(dst, `,"stack":`...)
+		dst = appendJSONString(dst, e.Stack)
+	}
+	return append(dst, '}')
+}
+
+// reservedKeys are the keys the structured encoders write for the entry itself
+var reservedKeys = map[string]bool{
+	"time": true, "level": true, "tag": true, "caller": true,
+	"func": true, "msg": true, "stack": true,
+}
+
+// structuredKey namespaces a field key that collides with an entry key, so a
+// field never replaces the message or timestamp for parsers keeping the last
+// duplicate
+func structuredKey(key string) string {
+	if reservedKeys[key] {
+		return "fields." + key
+	}
+	return key
+}
+
+func appendJSONString(dst []byte, s string) []byte {
+	b, _ := json.Marshal(s)
+	return append(dst, b...)
+}
+
+// appendJSONValue marshals v, errors and values that cannot be marshalled are
+// written as their string form so a bad field never drops the whole line
+func appendJSONValue(dst []byte, v any) []byte {
+	switch v := v.(type) {
+	case error:
+		return appendJSONString(dst, v.Error())
+	case json.Marshaler:
+	case fmt.Stringer:
+		return appendJSONString(dst, v.String())
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return appendJSONString(dst, fmt.Sprint(v))
+	}
+	return append(dst, b...)
+}
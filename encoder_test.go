@@ -0,0 +1,177 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONEncoder_Encode(t *testing.T) {
+	e := &Entry{
+		Time:    time.Date(2025, 5, 23, 10, 4, 5, 0, time.UTC),
+		Level:   LevelWarn,
+		Tag:     "GPIO",
+		Message: "pin \"17\" stuck\n",
+		Fields: []Field{
+			{Key: "pin", Value: 17},
+			{Key: "err", Value: errors.New("timeout")},
+		},
+	}
+
+	line := NewJSONEncoder().Encode(nil, e)
+
+	var got map[string]any
+	if err := json.Unmarshal(line, &got); err != nil {
+		t.Fatalf("Expected valid JSON but got %q: %v", line, err)
+	}
+
+	want := map[string]any{
+		"time":  "2025-05-23T10:04:05.000Z",
+		"level": "warn",
+		"tag":   "GPIO",
+		"msg":   "pin \"17\" stuck\n",
+		"pin":   float64(17),
+		"err":   "timeout",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Expected %s=%v but got %v", k, v, got[k])
+		}
+	}
+}
+
+func TestJSONEncoder_ReservedKeys(t *testing.T) {
+	e := &Entry{
+		Time:    time.Date(2025, 5, 23, 10, 4, 5, 0, time.UTC),
+		Level:   LevelInfo,
+		Message: "m",
+		Fields:  []Field{{Key: "msg", Value: "x"}, {Key: "time", Value: 1}},
+	}
+
+	line := NewJSONEncoder().Encode(nil, e)
+
+	var got map[string]any
+	if err := json.Unmarshal(line, &got); err != nil {
+		t.Fatalf("Expected valid JSON but got %q: %v", line, err)
+	}
+	if got["msg"] != "m" || got["time"] != "2025-05-23T10:04:05.000Z" {
+		t.Errorf("Expected fields to keep msg and time but got: %s", line)
+	}
+	if got["fields.msg"] != "x" || got["fields.time"] != float64(1) {
+		t.Errorf("Expected namespaced fields but got: %s", line)
+	}
+}
+
+func TestLoggerSync_JSONFileTextConsole(t *testing.T) {
+	dir := t.TempDir()
+	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	logger.SetFileEncoder(NewJSONEncoder())
+	logger.SetInfoStyle(StyleFgGreen)
+
+	logger.With("device", "ABA11").Info("JSON file message")
+
+	output := buf.waitLines(1)
+
+	if !strings.Contains(output, "\033[32m") || !strings.Contains(output, "[INFO ]") {
+		t.Errorf("Expected colored text on console but got: %q", output)
+	}
+
+	logger.Flush()
+	data, err := os.ReadFile(filepath.Join(dir, fileNameGenerator("OBJ")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Expected a JSON line in file but got %q: %v", data, err)
+	}
+
+	if got["msg"] != "JSON file message" || got["device"] != "ABA11" {
+		t.Errorf("Unexpected JSON line: %q", data)
+	}
+}
+
+func TestLoggerAsync_JSONConsole(t *testing.T) {
+	logger := NewAsync("TEST", 10, false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetConsoleEncoder(NewJSONEncoder())
+	logger.SetInfoStyle(StyleFgGreen)
+
+	logger.Info("JSON console message")
+
+	output := buf.waitLines(1)
+
+	if strings.Contains(output, "\033[") {
+		t.Errorf("Expected no ANSI escapes in JSON output but got: %q", output)
+	}
+
+	if !strings.Contains(output, `"msg":"JSON console message"`) {
+		t.Errorf("Expected JSON message but got: %q", output)
+	}
+}
+
+func TestLoggerAsync_SwapEncoders(t *testing.T) {
+	logger := NewAsync("TEST", 10, false)
+	logger.SetOutput(&testBuffer{})
+	logger.SetWriteFilesEnable(t.TempDir(), "OBJ")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 100 {
+			logger.Info("swapping", i)
+		}
+	}()
+	for range 20 {
+		logger.SetConsoleEncoder(NewJSONEncoder())
+		logger.SetFileEncoder(NewLogfmtEncoder())
+		if err := logger.SetConsoleLayout("{level} {msg}"); err != nil {
+			t.Fatal(err)
+		}
+		logger.SetFileEncoder(NewTextEncoder())
+	}
+	<-done
+
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAppendTimestamp(t *testing.T) {
+	times := []time.Time{
+		time.Date(2025, 5, 23, 10, 4, 5, 0, time.UTC),
+		time.Date(999, 1, 2, 0, 0, 0, 7_000_000, time.UTC),
+		time.Date(2025, 12, 31, 23, 59, 59, 999_999_999, time.UTC),
+		time.Now(),
+	}
+	for _, tm := range times {
+		want := tm.Format("2006-01-02 15:04:05.000")
+		if got := string(appendTimestamp(nil, tm)); got != want {
+			t.Errorf("Expected %q but got %q", want, got)
+		}
+	}
+}
+
+func TestTextEncoder_Tag(t *testing.T) {
+	for tag, want := range map[string]string{
+		"":         "[       ]",
+		"GPIO":     "[GPIO   ]",
+		"SEVENCH":  "[SEVENCH]",
+		"TOO_LONG": "[TOO_LONG]",
+	} {
+		if got := string(appendTag(nil, tag)); got != want {
+			t.Errorf("Expected %q but got %q", want, got)
+		}
+	}
+}
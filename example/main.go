@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"time"
 
 	"github.com/ABA-Developer/go-logger"
@@ -58,5 +59,5 @@ func AsyncImplementation() {
 			break
 		}
 	}
-	logger.Flush()
+	logger.Close(context.Background())
 }
@@ -0,0 +1,50 @@
+package logger
+
+// badKey is used when With receives a value without a matching key
+const badKey = "!BADKEY"
+
+// Field is a single structured key/value pair attached to a log line
+type Field struct {
+	Key   string
+	Value any
+}
+
+// appendFields converts alternating keys and values into fields and appends
+// them to a copy of parent, so siblings created from the same parent never
+// share a backing array
+func appendFields(parent []Field, keyvals ...any) []Field {
+	fields := make([]Field, len(parent), len(parent)+len(keyvals)/2+1)
+	copy(fields, parent)
+
+	for i := 0; i < len(keyvals); i++ {
+		switch k := keyvals[i].(type) {
+		case Field:
+			fields = append(fields, k)
+		case string:
+			if i+1 < len(keyvals) {
+				fields = append(fields, Field{Key: k, Value: keyvals[i+1]})
+				i++
+			} else {
+				fields = append(fields, Field{Key: badKey, Value: k})
+			}
+		default:
+			fields = append(fields, Field{Key: badKey, Value: k})
+		}
+	}
+	return fields
+}
+
+// appendFieldsText renders fields as " key=value key=value" using the same
+// quoting as the logfmt encoder so the line stays unambiguous, keys get the
+// field key style of ls
+func appendFieldsText(dst []byte, fields []Field, ls *lineStyle) []byte {
+	for _, f := range fields {
+		dst = append(dst, ' ')
+		dst = ls.open(dst, partKey)
+		dst = appendLogfmtKey(dst, f.Key)
+		dst = ls.close(dst, partKey)
+		dst = append(dst, '=')
+		dst = appendLogfmtValue(dst, valueString(f.Value))
+	}
+	return dst
+}
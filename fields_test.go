@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoggerSync_With_Fields(t *testing.T) {
+	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	child := logger.With("device", "ABA11", "request", 42)
+
+	child.Info("Child message")
+	logger.Info("Parent message")
+
+	output := buf.waitLines(2)
+
+	if !strings.Contains(output, "Child message device=ABA11 request=42") {
+		t.Errorf("Expected child fields but got: %q", output)
+	}
+
+	if strings.Contains(output, "Parent message device") {
+		t.Errorf("Expected parent without fields but got: %q", output)
+	}
+}
+
+func TestLoggerSync_With_Nested(t *testing.T) {
+	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	child := logger.With("device", "ABA11")
+	a := child.With("step", "a")
+	b := child.With("step", "b")
+
+	a.Info("first")
+	b.Info("second")
+
+	output := buf.waitLines(2)
+
+	if !strings.Contains(output, "first device=ABA11 step=a") {
+		t.Errorf("Expected nested fields but got: %q", output)
+	}
+
+	if !strings.Contains(output, "second device=ABA11 step=b") {
+		t.Errorf("Expected sibling fields but got: %q", output)
+	}
+}
+
+func TestLoggerSync_With_SharesStyleAndFile(t *testing.T) {
+	dir := t.TempDir()
+	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	child := logger.With("device", "ABA11")
+	logger.SetInfoStyle(StyleFgGreen)
+
+	child.Infof("message %d", 1)
+
+	output := buf.waitLines(1)
+
+	if !strings.Contains(output, "\033[32m") {
+		t.Errorf("Expected parent style on child but got: %q", output)
+	}
+
+	logger.Flush()
+	data, err := os.ReadFile(filepath.Join(dir, fileNameGenerator("OBJ")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), "message 1 device=ABA11") {
+		t.Errorf("Expected fields in file but got: %q", data)
+	}
+}
+
+func TestLoggerAsync_With_Fields(t *testing.T) {
+	logger := NewAsync("TEST", 10, false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	child := logger.With("device", "ABA11", "note", "two words")
+
+	child.Info("Child message")
+
+	output := buf.waitLines(1)
+
+	if !strings.Contains(output, `Child message device=ABA11 note="two words"`) {
+		t.Errorf("Expected child fields but got: %q", output)
+	}
+}
+
+func TestAppendFields_BadKey(t *testing.T) {
+	fields := appendFields(nil, "key", 1, 2)
+
+	if len(fields) != 2 || fields[1].Key != badKey {
+		t.Errorf("Expected dangling value under %s but got: %v", badKey, fields)
+	}
+}
@@ -0,0 +1,331 @@
+package logger
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FsyncPolicy selects when buffered log file writes are committed to stable
+// storage with fsync
+type FsyncPolicy int
+
+const (
+	// FsyncNever leaves committing to the operating system
+	FsyncNever FsyncPolicy = iota
+	// FsyncOnFlush commits every time the buffer is written out, or after
+	// every line when the file is not buffered
+	FsyncOnFlush
+	// FsyncOnError writes out and commits the file after every Error, Panic
+	// and Fatal message
+	FsyncOnError
+)
+
+// syncFile commits a log file to stable storage, tests replace it to count
+// the commits
+var syncFile = (*os.File).Sync
+
+// FileBuffering configures batched log file writes, the zero value writes
+// every line with its own syscall
+type FileBuffering struct {
+	// Size is the buffer size in bytes, zero disables buffering
+	Size int
+	// FlushInterval writes the buffer out periodically, zero writes it out
+	// only when it is full, on Sync and on Close
+	FlushInterval time.Duration
+	// Fsync selects when the file is committed to stable storage, it applies
+	// to unbuffered files too
+	Fsync FsyncPolicy
+}
+
+// logFile is the daily log file written by SetWriteFilesEnable, it rotates on
+// the ChangeFileRoutine schedule and optionally once it grows past maxSize
+type logFile struct {
+	mu         sync.Mutex
+	enabled    bool
+	path       string
+	objectName string
+	fileName   string
+	file       *os.File
+	size       int64
+	maxSize    int64
+	maxBackups int
+	retention  RetentionPolicy
+	compress   bool
+	compressMu sync.Mutex
+	compressWG sync.WaitGroup
+	buffering  FileBuffering
+	buf        *bufio.Writer
+	line       []byte
+	stopFlush  chan struct{}
+	loc        *time.Location
+}
+
+// SetFileBuffering batches log file writes in a buffer that is written out
+// when full, every FlushInterval, on Sync and on Close. It works for both
+// loggers and trades a little durability for far fewer syscalls.
+// Example:
+// logger.SetFileBuffering(logger.FileBuffering{Size: 64 << 10, FlushInterval: time.Second, Fsync: logger.FsyncOnError})
+func (l *core) SetFileBuffering(b FileBuffering) {
+	f := l.file
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flushLocked()
+	f.buffering = b
+	f.buf = nil
+	if b.Size > 0 && f.file != nil {
+		f.buf = bufio.NewWriterSize(f.file, b.Size)
+	}
+	if f.stopFlush != nil {
+		close(f.stopFlush)
+		f.stopFlush = nil
+	}
+	if b.Size > 0 && b.FlushInterval > 0 {
+		f.stopFlush = make(chan struct{})
+		go f.flushRoutine(b.FlushInterval, f.stopFlush, l.done)
+	}
+}
+
+// flushRoutine writes the buffer out every interval until stop or done is closed
+func (f *logFile) flushRoutine(interval time.Duration, stop chan struct{}, done chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			f.mu.Lock()
+			f.flushLocked()
+			f.mu.Unlock()
+		case <-stop:
+			return
+		case <-done:
+			return
+		}
+	}
+}
+
+// flushLocked writes the buffer out and applies FsyncOnFlush, the caller
+// holds f.mu
+func (f *logFile) flushLocked() error {
+	if f.buf == nil || f.buf.Buffered() == 0 {
+		return nil
+	}
+	err := f.buf.Flush()
+	if err == nil && f.buffering.Fsync == FsyncOnFlush {
+		err = syncFile(f.file)
+	}
+	return err
+}
+
+func (f *logFile) open(path string, objectName string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objectName = objectName
+	f.path = newFolderPath(path)
+	f.openCurrent()
+	f.enabled = true
+	if f.compress {
+		f.compressInBackground()
+	}
+}
+
+// isEnabled reports whether SetWriteFilesEnable opened the file and Close did
+// not close it yet
+func (f *logFile) isEnabled() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.enabled
+}
+
+// now returns the current time in the location set with SetFileLocation
+func (f *logFile) now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.nowLocked()
+}
+
+func (f *logFile) nowLocked() time.Time {
+	if f.loc != nil {
+		return time.Now().In(f.loc)
+	}
+	return time.Now()
+}
+
+// openCurrent opens today's file in append mode, the caller holds f.mu
+func (f *logFile) openCurrent() {
+	f.fileName = fileNameAt(f.objectName, f.nowLocked())
+	f.file = createAndAppendObject(f.fileName, f.path)
+	f.size = 0
+	if f.file != nil {
+		if info, err := f.file.Stat(); err == nil {
+			f.size = info.Size()
+		}
+	}
+	f.resetBuffer()
+}
+
+// resetBuffer points the buffer at the current file, the caller holds f.mu
+func (f *logFile) resetBuffer() {
+	if f.buffering.Size <= 0 || f.file == nil {
+		f.buf = nil
+	} else if f.buf == nil {
+		f.buf = bufio.NewWriterSize(f.file, f.buffering.Size)
+	} else {
+		f.buf.Reset(f.file)
+	}
+}
+
+// writeEntry encodes e with enc and writes it as one line
+func (f *logFile) writeEntry(e *Entry, enc Encoder) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.enabled || f.file == nil {
+		return
+	}
+	f.line = append(enc.Encode(f.line[:0], e), '\n')
+	f.writeLocked(f.line)
+	if f.file == nil {
+		return
+	}
+	switch {
+	case e.Level >= LevelError && f.buffering.Fsync == FsyncOnError:
+		if f.buf == nil || f.buf.Flush() == nil {
+			syncFile(f.file)
+		}
+	case f.buf == nil && f.buffering.Fsync == FsyncOnFlush:
+		// Every unbuffered line is a flush of its own
+		syncFile(f.file)
+	}
+}
+
+// writeLocked writes a complete line, rotating first if it would make the
+// file grow past maxSize, the caller holds f.mu
+func (f *logFile) writeLocked(line []byte) {
+	n := int64(len(line))
+	if f.maxSize > 0 && f.size > 0 && f.size+n > f.maxSize {
+		f.rotateBySize()
+	}
+	if f.file == nil {
+		return
+	}
+	if f.buf != nil {
+		f.buf.Write(line)
+	} else {
+		f.file.Write(line)
+	}
+	f.size += n
+}
+
+// rotateDaily closes the current file and opens the one for today
+func (f *logFile) rotateDaily() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.enabled {
+		return
+	}
+	// Close first previous object file
+	if f.file != nil {
+		f.flushLocked()
+		f.file.Close()
+	}
+	// Create new file object with the append mode
+	f.openCurrent()
+	f.retain(false)
+	if f.compress {
+		f.compressInBackground()
+	}
+}
+
+// rotateBySize renames the current file to index 1, shifting older backups
+// up by one and removing the ones beyond maxBackups, the caller holds f.mu
+func (f *logFile) rotateBySize() {
+	f.flushLocked()
+	f.file.Close()
+
+	backups := f.backupIndexes()
+	for i := len(backups) - 1; i >= 0; i-- {
+		idx := backups[i]
+		from := filepath.Join(f.path, backupName(f.fileName, idx))
+		if f.maxBackups > 0 && idx >= f.maxBackups {
+			os.Remove(from)
+			continue
+		}
+		os.Rename(from, filepath.Join(f.path, backupName(f.fileName, idx+1)))
+	}
+	os.Rename(filepath.Join(f.path, f.fileName), filepath.Join(f.path, backupName(f.fileName, 1)))
+
+	f.file = createAndAppendObject(f.fileName, f.path)
+	f.size = 0
+	f.resetBuffer()
+	f.retain(false)
+}
+
+// backupIndexes returns the indexes of the existing backups of the current
+// file in ascending order
+func (f *logFile) backupIndexes() []int {
+	base := strings.TrimSuffix(f.fileName, ".txt") + "."
+	entries, err := os.ReadDir(f.path)
+	if err != nil {
+		return nil
+	}
+	var indexes []int
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, base) || !strings.HasSuffix(name, ".txt") {
+			continue
+		}
+		idx, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, base), ".txt"))
+		if err == nil && idx > 0 {
+			indexes = append(indexes, idx)
+		}
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// close commits and closes the file, later writes are ignored
+func (f *logFile) close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.enabled = false
+	if f.stopFlush != nil {
+		close(f.stopFlush)
+		f.stopFlush = nil
+	}
+	if f.file == nil {
+		return nil
+	}
+	err := f.flushLocked()
+	if serr := syncFile(f.file); err == nil {
+		err = serr
+	}
+	if cerr := f.file.Close(); err == nil {
+		err = cerr
+	}
+	f.file = nil
+	f.buf = nil
+	return err
+}
+
+func (f *logFile) sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.enabled && f.file != nil {
+		if err := f.flushLocked(); err != nil {
+			return err
+		}
+		return syncFile(f.file)
+	}
+	return nil
+}
+
+// backupName inserts the backup index before the extension
+// Example: backupName("2025-05-23:ABA11.txt", 1) returns "2025-05-23:ABA11.1.txt"
+func backupName(fileName string, idx int) string {
+	return strings.TrimSuffix(fileName, ".txt") + "." + strconv.Itoa(idx) + ".txt"
+}
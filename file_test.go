@@ -0,0 +1,246 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoggerSync_RotateBySize(t *testing.T) {
+	dir := t.TempDir()
+	logger := NewSync("TEST", false)
+	logger.SetOutput(&testBuffer{})
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	logger.SetMaxFileSize(200)
+	logger.SetMaxBackups(2)
+
+	for i := range 20 {
+		logger.Infof("message number %02d", i)
+	}
+	logger.Flush()
+
+	name := fileNameGenerator("OBJ")
+	for _, f := range []string{name, backupName(name, 1), backupName(name, 2)} {
+		info, err := os.Stat(filepath.Join(dir, f))
+		if err != nil {
+			t.Fatalf("Expected %s to exist: %v", f, err)
+		}
+		if info.Size() > 200 {
+			t.Errorf("Expected %s to stay below 200 bytes but got %d", f, info.Size())
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, backupName(name, 3))); !os.IsNotExist(err) {
+		t.Errorf("Expected only 2 backups to be kept")
+	}
+
+	current, _ := os.ReadFile(filepath.Join(dir, name))
+	newest, _ := os.ReadFile(filepath.Join(dir, backupName(name, 1)))
+	if !strings.Contains(string(current), "message number 19") {
+		t.Errorf("Expected the last message in the current file but got: %q", current)
+	}
+	if strings.Contains(string(newest), "message number 19") || len(newest) == 0 {
+		t.Errorf("Expected older messages in the first backup but got: %q", newest)
+	}
+}
+
+func TestLogFile_RotateBySize_Unlimited(t *testing.T) {
+	dir := t.TempDir()
+	f := &logFile{maxSize: 100}
+	f.open(dir, "OBJ")
+
+	for range 10 {
+		f.writeEntry(&Entry{Message: strings.Repeat("x", 60)}, messageEncoder{})
+	}
+	f.sync()
+
+	if got := f.backupIndexes(); len(got) != 9 || got[8] != 9 {
+		t.Errorf("Expected 9 backups but got: %v", got)
+	}
+}
+
+// messageEncoder writes only the message
+type messageEncoder struct{}
+
+func (messageEncoder) Encode(dst []byte, e *Entry) []byte {
+	return append(dst, e.Message...)
+}
+
+func TestLoggerSync_ChangeFileRoutine_Enable(t *testing.T) {
+	logger := NewSync("TEST", false)
+	defer logger.Close(context.Background())
+
+	if err := logger.ChangeFileRoutine(0, 0); err == nil {
+		t.Error("Expected an error before SetWriteFilesEnable")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.SetWriteFilesEnable(t.TempDir(), "OBJ")
+	}()
+	logger.ChangeFileRoutine(0, 0)
+	<-done
+
+	if err := logger.ChangeFileRoutine(0, 0); err != nil {
+		t.Errorf("Expected no error after SetWriteFilesEnable but got: %v", err)
+	}
+}
+
+func TestBackupName(t *testing.T) {
+	if got := backupName("2025-05-23:ABA11.txt", 1); got != "2025-05-23:ABA11.1.txt" {
+		t.Errorf("Unexpected backup name: %s", got)
+	}
+}
+
+func readLogFile(t *testing.T, dir string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, fileNameGenerator("OBJ")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestLoggerSync_FileBuffering(t *testing.T) {
+	dir := t.TempDir()
+	logger := NewSync("TEST", false)
+	logger.SetOutput(&testBuffer{})
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	logger.SetFileBuffering(FileBuffering{Size: 4096})
+
+	logger.Info("buffered line")
+	if got := readLogFile(t, dir); got != "" {
+		t.Errorf("Expected the line to stay in the buffer but got: %q", got)
+	}
+
+	logger.Sync()
+	if got := readLogFile(t, dir); !strings.Contains(got, "buffered line") {
+		t.Errorf("Expected Sync to write the buffer out but got: %q", got)
+	}
+}
+
+func TestLoggerSync_FileBuffering_FsyncOnError(t *testing.T) {
+	dir := t.TempDir()
+	logger := NewSync("TEST", false)
+	logger.SetOutput(&testBuffer{})
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	logger.SetFileBuffering(FileBuffering{Size: 4096, Fsync: FsyncOnError})
+
+	logger.Warn("first")
+	logger.Error("second")
+
+	got := readLogFile(t, dir)
+	if !strings.Contains(got, "first") || !strings.Contains(got, "second") {
+		t.Errorf("Expected an error to write the buffer out but got: %q", got)
+	}
+}
+
+func TestLoggerSync_FileBuffering_Panic(t *testing.T) {
+	dir := t.TempDir()
+	logger := NewSync("TEST", false)
+	logger.SetOutput(&testBuffer{})
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	logger.SetFileBuffering(FileBuffering{Size: 4096})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected Panic to panic")
+			}
+			if got := readLogFile(t, dir); !strings.Contains(got, "going down") {
+				t.Errorf("Expected the panic line in the file before panicking but got: %q", got)
+			}
+		}()
+		logger.Panic("going down")
+	}()
+}
+
+func TestLoggerSync_Fsync_Unbuffered(t *testing.T) {
+	syncs := 0
+	syncFile = func(f *os.File) error {
+		syncs++
+		return f.Sync()
+	}
+	defer func() { syncFile = (*os.File).Sync }()
+
+	logger := NewSync("TEST", false)
+	logger.SetOutput(&testBuffer{})
+	logger.SetWriteFilesEnable(t.TempDir(), "OBJ")
+
+	logger.SetFileBuffering(FileBuffering{Fsync: FsyncOnError})
+	logger.Info("first")
+	logger.Warn("second")
+	if syncs != 0 {
+		t.Errorf("Expected no fsync below Error but got %d", syncs)
+	}
+	logger.Error("third")
+	if syncs != 1 {
+		t.Errorf("Expected an fsync after an error but got %d", syncs)
+	}
+
+	syncs = 0
+	logger.SetFileBuffering(FileBuffering{Fsync: FsyncOnFlush})
+	logger.Info("fourth")
+	logger.Info("fifth")
+	if syncs != 2 {
+		t.Errorf("Expected an fsync after every line but got %d", syncs)
+	}
+}
+
+func TestLoggerAsync_FileBuffering_Interval(t *testing.T) {
+	dir := t.TempDir()
+	logger := NewAsync("TEST", 10, false)
+	logger.SetOutput(&testBuffer{})
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	logger.SetFileBuffering(FileBuffering{Size: 4096, FlushInterval: 5 * time.Millisecond, Fsync: FsyncOnFlush})
+	defer logger.Close(context.Background())
+
+	logger.Info("periodic line")
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(readLogFile(t, dir), "periodic line") {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the flush interval to write the buffer out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLoggerAsync_FileBuffering_RotateAndClose(t *testing.T) {
+	dir := t.TempDir()
+	logger := NewAsync("TEST", 10, false)
+	logger.SetOutput(&testBuffer{})
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	logger.SetFileBuffering(FileBuffering{Size: 1024})
+	logger.SetMaxFileSize(300)
+
+	for i := range 30 {
+		logger.Infof("message number %02d", i)
+	}
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data) > 300 {
+			t.Errorf("Expected %s to stay below 300 bytes but got %d", f.Name(), len(data))
+		}
+		total += strings.Count(string(data), "\n")
+	}
+	if total != 30 {
+		t.Errorf("Expected 30 lines across the rotated files but got %d", total)
+	}
+}
@@ -0,0 +1,274 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// layoutField is a placeholder of a layout template
+type layoutField int8
+
+const (
+	layoutLiteral layoutField = iota
+	layoutTime
+	layoutLevel
+	layoutTag
+	layoutCaller
+	layoutMsg
+	layoutFields
+)
+
+var layoutNames = map[string]layoutField{
+	"time":   layoutTime,
+	"level":  layoutLevel,
+	"tag":    layoutTag,
+	"caller": layoutCaller,
+	"msg":    layoutMsg,
+	"fields": layoutFields,
+}
+
+// layoutParts maps the placeholders styled as a whole to their line part
+var layoutParts = map[layoutField]stylePart{
+	layoutTime: partTime,
+	layoutTag:  partTag,
+}
+
+// levelNames are the upper case level names written by {level}
+var levelNames = [...]string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "PANIC", "FATAL"}
+
+// layoutSegment is literal text or a placeholder with its directives
+type layoutSegment struct {
+	field   layoutField
+	literal string
+	left    bool
+	width   int
+	max     int
+}
+
+type layoutEncoder struct {
+	segments []layoutSegment
+}
+
+// NewLayoutEncoder returns a text encoder writing lines laid out by template.
+// The placeholders are {time}, {level}, {tag}, {caller}, {msg} and {fields},
+// everything else is copied as is, {{ and }} write literal braces. A
+// placeholder takes printf like directives after a colon: a width pads it
+// with spaces on the left, a leading - pads on the right and .N truncates it
+// to N characters. A placeholder that renders empty, e.g. {fields} without
+// fields, drops the space separating it. The template is parsed once, stack
+// traces are written after the line like the default encoder does.
+// Example:
+// enc, err := logger.NewLayoutEncoder("{time} {level:-5} [{tag:-7.7}] {msg} {fields}")
+// log format: 2025-05-23 10:04:05.000 INFO  [GPIO   ] pin configured pin=17
+func NewLayoutEncoder(template string) (Encoder, error) {
+	var segments []layoutSegment
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			segments = append(segments, layoutSegment{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		switch {
+		case c == '{' && strings.HasPrefix(template[i:], "{{"):
+			literal.WriteByte('{')
+			i++
+		case c == '}' && strings.HasPrefix(template[i:], "}}"):
+			literal.WriteByte('}')
+			i++
+		case c == '{':
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("layout: unclosed placeholder at offset %d", i)
+			}
+			seg, err := parsePlaceholder(template[i+1 : i+end])
+			if err != nil {
+				return nil, err
+			}
+			flush()
+			segments = append(segments, seg)
+			i += end
+		case c == '}':
+			return nil, fmt.Errorf("layout: unexpected } at offset %d, write }} for a literal brace", i)
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	flush()
+	return &layoutEncoder{segments: segments}, nil
+}
+
+// parsePlaceholder parses name or name:[-][width][.max]
+func parsePlaceholder(s string) (layoutSegment, error) {
+	name, spec, _ := strings.Cut(s, ":")
+	field, ok := layoutNames[name]
+	if !ok {
+		return layoutSegment{}, fmt.Errorf("layout: unknown placeholder {%s}", s)
+	}
+	seg := layoutSegment{field: field}
+
+	spec, seg.left = strings.CutPrefix(spec, "-")
+	width, limit, hasMax := strings.Cut(spec, ".")
+	var err error
+	if width != "" {
+		if seg.width, err = strconv.Atoi(width); err != nil || seg.width < 0 {
+			return layoutSegment{}, fmt.Errorf("layout: bad width in {%s}", s)
+		}
+	}
+	if hasMax {
+		if seg.max, err = strconv.Atoi(limit); err != nil || seg.max <= 0 {
+			return layoutSegment{}, fmt.Errorf("layout: bad truncation in {%s}", s)
+		}
+	}
+	return seg, nil
+}
+
+// SetConsoleLayout lays console lines out with template, see NewLayoutEncoder.
+// The level styles still apply.
+// Example:
+// err := logger.SetConsoleLayout("{time} {level:-5} {msg} {fields}")
+func (l *core) SetConsoleLayout(template string) error {
+	enc, err := NewLayoutEncoder(template)
+	if err != nil {
+		return err
+	}
+	l.SetConsoleEncoder(enc)
+	return nil
+}
+
+// SetFileLayout lays the lines of the log file out with template, see
+// NewLayoutEncoder
+func (l *core) SetFileLayout(template string) error {
+	enc, err := NewLayoutEncoder(template)
+	if err != nil {
+		return err
+	}
+	l.SetFileEncoder(enc)
+	return nil
+}
+
+func (enc *layoutEncoder) Encode(dst []byte, e *Entry) []byte {
+	return enc.encodeStyled(dst, e, nil)
+}
+
+func (enc *layoutEncoder) encodeStyled(dst []byte, e *Entry, ls *lineStyle) []byte {
+	start := len(dst)
+	// separator is whether dst ends with a space of the template, skipSpace
+	// drops the space after an empty placeholder starting the line
+	separator, skipSpace := false, false
+	for _, seg := range enc.segments {
+		if seg.field == layoutLiteral {
+			literal := seg.literal
+			if skipSpace {
+				literal = strings.TrimPrefix(literal, " ")
+			}
+			dst = append(dst, literal...)
+			separator = strings.HasSuffix(literal, " ")
+			skipSpace = false
+			continue
+		}
+		before := len(dst)
+		part, styled := layoutParts[seg.field]
+		if styled {
+			dst = ls.open(dst, part)
+		}
+		from := len(dst)
+		fieldStyle := ls
+		if seg.width > 0 || seg.max > 0 {
+			// Escapes inside the field would count towards its width
+			fieldStyle = nil
+		}
+		dst = appendLayoutField(dst, seg.field, e, fieldStyle)
+		dst = fitLayoutField(dst, from, seg)
+		if len(dst) == from {
+			// Drop the separator an empty placeholder leaves, e.g. {fields}
+			// without fields
+			dst = dst[:before]
+			switch {
+			case separator:
+				dst = dst[:len(dst)-1]
+			case len(dst) == start:
+				skipSpace = true
+			}
+			separator = false
+			continue
+		}
+		if styled {
+			dst = ls.close(dst, part)
+		}
+		separator, skipSpace = false, false
+	}
+	if e.Stack != "" {
+		dst = appendIndented(dst, e.Stack)
+	}
+	return dst
+}
+
+func appendLayoutField(dst []byte, field layoutField, e *Entry, ls *lineStyle) []byte {
+	switch field {
+	case layoutTime:
+		return appendTime(dst, e, textTimeLayout)
+	case layoutLevel:
+		if e.Level >= LevelTrace && int(e.Level) < len(levelNames) {
+			return append(dst, levelNames[e.Level]...)
+		}
+		return append(dst, strings.ToUpper(e.Level.String())...)
+	case layoutTag:
+		return append(dst, e.Tag...)
+	case layoutCaller:
+		if e.Caller.IsZero() {
+			return dst
+		}
+		dst = append(dst, e.Caller.File...)
+		dst = append(dst, ':')
+		dst = strconv.AppendInt(dst, int64(e.Caller.Line), 10)
+		dst = append(dst, ' ')
+		return append(dst, e.Caller.Function...)
+	case layoutMsg:
+		return appendMessage(dst, e, ls)
+	case layoutFields:
+		if len(e.Fields) == 0 {
+			return dst
+		}
+		// appendFieldsText starts every field with a space, the template
+		// decides what comes before the first one
+		from := len(dst)
+		dst = appendFieldsText(dst, e.Fields, ls)
+		return append(dst[:from], dst[from+1:]...)
+	}
+	return dst
+}
+
+// fitLayoutField truncates and pads dst[from:] as the directives of seg say,
+// widths count characters rather than bytes
+func fitLayoutField(dst []byte, from int, seg layoutSegment) []byte {
+	n := utf8.RuneCount(dst[from:])
+	if seg.max > 0 && n > seg.max {
+		cut := from
+		for range seg.max {
+			_, size := utf8.DecodeRune(dst[cut:])
+			cut += size
+		}
+		dst = dst[:cut]
+		n = seg.max
+	}
+	if n >= seg.width {
+		return dst
+	}
+	pad := seg.width - n
+	for range pad {
+		dst = append(dst, ' ')
+	}
+	if !seg.left {
+		copy(dst[from+pad:], dst[from:len(dst)-pad])
+		for i := from; i < from+pad; i++ {
+			dst[i] = ' '
+		}
+	}
+	return dst
+}
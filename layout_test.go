@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLayoutEncoder_Encode(t *testing.T) {
+	e := &Entry{
+		Time:    time.Date(2025, 5, 23, 10, 4, 5, 0, time.UTC),
+		Level:   LevelWarn,
+		Tag:     "GATEWAY1",
+		Message: "pin stuck",
+		Fields:  []Field{{Key: "pin", Value: 17}},
+		Caller:  Caller{File: "gpio/pin.go", Line: 42, Function: "gpio.Set"},
+	}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"{time} {level} {tag} {caller} {msg} {fields}", "2025-05-23 10:04:05.000 WARN GATEWAY1 gpio/pin.go:42 gpio.Set pin stuck pin=17"},
+		{"[{level:-5}] [{tag:-7.7}]: {msg}", "[WARN ] [GATEWAY]: pin stuck"},
+		{"{level:6}|{msg:.3}|", "  WARN|pin|"},
+		{"{{{msg}}}", "{pin stuck}"},
+		{"{msg} {fields}", "pin stuck pin=17"},
+	}
+	for _, tt := range tests {
+		enc, err := NewLayoutEncoder(tt.template)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.template, err)
+		}
+		if got := string(enc.Encode(nil, e)); got != tt.want {
+			t.Errorf("%s: Expected %q but got %q", tt.template, tt.want, got)
+		}
+	}
+}
+
+func TestLayoutEncoder_EmptyPlaceholders(t *testing.T) {
+	enc, err := NewLayoutEncoder("{msg} {fields} {caller}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(enc.Encode(nil, &Entry{Message: "plain"})); got != "plain" {
+		t.Errorf("Expected the trailing spaces to be dropped but got %q", got)
+	}
+}
+
+func TestLayoutEncoder_KeepSpaces(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"{msg}", "trailing   "},
+		{"{level:-7}", "INFO   "},
+		{"{msg}| ", "trailing   | "},
+		{"{caller} {msg} {fields}", "trailing   "},
+	}
+	for _, tt := range tests {
+		enc, err := NewLayoutEncoder(tt.template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(enc.Encode(nil, &Entry{Level: LevelInfo, Message: "trailing   "})); got != tt.want {
+			t.Errorf("%s: Expected %q but got %q", tt.template, tt.want, got)
+		}
+	}
+}
+
+func TestLayoutEncoder_Truncate_Runes(t *testing.T) {
+	enc, err := NewLayoutEncoder("{msg:-4.3}|")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(enc.Encode(nil, &Entry{Message: "héllo"})); got != "hél |" {
+		t.Errorf("Expected the width to count characters but got %q", got)
+	}
+}
+
+func TestNewLayoutEncoder_Errors(t *testing.T) {
+	for _, template := range []string{"{msg", "{unknown}", "{msg:x}", "{msg:.0}", "msg}"} {
+		if _, err := NewLayoutEncoder(template); err == nil {
+			t.Errorf("Expected an error for %q", template)
+		}
+	}
+}
+
+func TestLoggerSync_Layouts(t *testing.T) {
+	dir := t.TempDir()
+	buf := &testBuffer{}
+	logger := NewSync("TEST", false)
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
+	logger.SetInfoStyle(StyleFgWhite)
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	if err := logger.SetConsoleLayout("{level:-5} {msg}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.SetFileLayout("{tag}: {msg} {fields}"); err != nil {
+		t.Fatal(err)
+	}
+
+	logger.With("pin", 17).Info("configured")
+	logger.Close(t.Context())
+
+	if got := buf.String(); got != "\033[37mINFO  configured\033[0m\n" {
+		t.Errorf("Expected the styled console layout but got %q", got)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, fileNameGenerator("OBJ")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "TEST: configured pin=17" {
+		t.Errorf("Expected the file layout but got %q", got)
+	}
+}
@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level is the severity of a log message
+type Level int8
+
+const (
+	LevelTrace Level = iota
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelPanic
+	LevelFatal
+	// LevelOff is above every level, it is used as a threshold to turn a
+	// feature such as stack traces off
+	LevelOff
+)
+
+// String returns the lower case level name used by structured encoders
+func (lv Level) String() string {
+	switch lv {
+	case LevelTrace:
+		return "trace"
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	case LevelPanic:
+		return "panic"
+	case LevelFatal:
+		return "fatal"
+	case LevelOff:
+		return "off"
+	}
+	return fmt.Sprintf("Level(%d)", int8(lv))
+}
+
+// ParseLevel returns the level named s, matching is case insensitive and
+// "warning" is accepted for LevelWarn
+// Example:
+// lv, err := logger.ParseLevel(os.Getenv("LOG_LEVEL"))
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "trace":
+		return LevelTrace, nil
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	case "panic":
+		return LevelPanic, nil
+	case "fatal":
+		return LevelFatal, nil
+	case "off":
+		return LevelOff, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", s)
+}
+
+// MarshalText implements encoding.TextMarshaler so levels can be stored in
+// JSON, TOML or YAML config files
+func (lv Level) MarshalText() ([]byte, error) {
+	return []byte(lv.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseLevel
+func (lv *Level) UnmarshalText(text []byte) error {
+	parsed, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*lv = parsed
+	return nil
+}
+
+// key returns the bracketed level used by the text format
+func (lv Level) key() string {
+	switch lv {
+	case LevelTrace:
+		return traceKey
+	case LevelDebug:
+		return debugKey
+	case LevelInfo:
+		return infoKey
+	case LevelWarn:
+		return warnKey
+	case LevelError:
+		return errorKey
+	case LevelPanic:
+		return panicKey
+	case LevelFatal:
+		return fatalKey
+	}
+	return "[?????] "
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	for lv := LevelTrace; lv <= LevelFatal; lv++ {
+		got, err := ParseLevel(strings.ToUpper(lv.String()))
+		if err != nil || got != lv {
+			t.Errorf("Expected %v to round trip but got %v, %v", lv, got, err)
+		}
+	}
+
+	if got, _ := ParseLevel(" warning "); got != LevelWarn {
+		t.Errorf("Expected warning to parse as warn but got %v", got)
+	}
+
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Errorf("Expected an error for an unknown level")
+	}
+}
+
+func TestLevel_UnmarshalText(t *testing.T) {
+	var lv Level
+	if err := lv.UnmarshalText([]byte("error")); err != nil || lv != LevelError {
+		t.Errorf("Expected error level but got %v, %v", lv, err)
+	}
+}
+
+func TestLoggerSync_Trace(t *testing.T) {
+	logger := NewSync("TEST", true)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
+	logger.SetTraceStyle(StyleFgBlue)
+
+	logger.Trace("hidden trace")
+	if logger.GetLevel() != LevelDebug || buf.String() != "" {
+		t.Errorf("Expected trace to be disabled in debug mode but got: %q", buf.String())
+	}
+
+	logger.SetLevel(LevelTrace)
+	logger.Tracef("visible %s", "trace")
+
+	output := buf.waitLines(1)
+	if !strings.Contains(output, "\033[34m") || !strings.Contains(output, traceKey+"[TEST   ]: visible trace") {
+		t.Errorf("Expected styled trace line but got: %q", output)
+	}
+}
+
+func TestLoggerSync_SetLevel(t *testing.T) {
+	logger := NewSync("TEST", true)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetLevel(LevelWarn)
+
+	logger.Debug("no debug")
+	logger.Info("no info")
+	logger.Warn("warn shown")
+	logger.Errorf("error %s", "shown")
+
+	output := buf.waitLines(2)
+	if strings.Contains(output, "no ") || strings.Count(output, "\n") != 2 {
+		t.Errorf("Expected only warn and error but got: %q", output)
+	}
+}
+
+func TestLoggerAsync_SetLevel_Concurrent(t *testing.T) {
+	logger := NewAsync("TEST", 100, false)
+	logger.SetOutput(&testBuffer{})
+	child := logger.With("child", true)
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				child.Debugf("goroutine %d message %d", i, j)
+				logger.SetLevel(Level(j % 3))
+				_ = logger.GetLevel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	logger.SetLevel(LevelError)
+	if child.(*LoggerAsync).GetLevel() != LevelError {
+		t.Errorf("Expected children to share the level of their parent")
+	}
+}
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// NewLogfmtEncoder returns an encoder writing logfmt lines, a field named like
+// one of the entry keys is written as fields.<key>
+// log format: time=... level=info tag=GPIO msg="pin configured" pin=17
+func NewLogfmtEncoder() Encoder {
+	return logfmtEncoder{}
+}
+
+type logfmtEncoder struct{}
+
+func (logfmtEncoder) Encode(dst []byte, e *Entry) []byte {
+	if !e.Time.IsZero() {
+		dst = append(dst, "time="...)
+		if e.timeLayout == "" || e.timeLayout == TimeFormatUnixMilli {
+			dst = appendTime(dst, e, structuredTimeLayout)
+		} else {
+			dst = appendLogfmtValue(dst, e.Time.Format(e.timeLayout))
+		}
+		dst = append(dst, ' ')
+	}
+	dst = append(dst, "level="...)
+	dst = append(dst, e.Level.String()...)
+	dst = append(dst, " tag="...)
+	dst = appendLogfmtValue(dst, e.Tag)
+	if !e.Caller.IsZero() {
+		dst = append(dst, " caller="...)
+		dst = appendLogfmtValue(dst, e.Caller.String())
+		dst = append(dst, " func="...)
+		dst = appendLogfmtValue(dst, e.Caller.Function)
+	}
+	dst = append(dst, " msg="...)
+	dst = appendLogfmtValue(dst, plainMessage(e))
+	for _, f := range e.Fields {
+		dst = append(dst, ' ')
+		dst = appendLogfmtKey(dst, structuredKey(f.Key))
+		dst = append(dst, '=')
+		dst = appendLogfmtValue(dst, valueString(f.Value))
+	}
+	if e.Stack != "" {
+		dst = append(dst, " stack="...)
+		dst = appendLogfmtValue(dst, e.Stack)
+	}
+	return dst
+}
+
+// appendLogfmtKey writes key with every character that would end the key
+// replaced by '_', logfmt has no way to quote keys
+func appendLogfmtKey(dst []byte, key string) []byte {
+	if key == "" {
+		return append(dst, '_')
+	}
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError {
+			dst = append(dst, '_')
+		} else {
+			dst = utf8.AppendRune(dst, r)
+		}
+	}
+	return dst
+}
+
+// appendLogfmtValue writes s bare when that is unambiguous and as a quoted
+// string with JSON escapes otherwise, the only ones logfmt decoders accept
+func appendLogfmtValue(dst []byte, s string) []byte {
+	if !needsQuote(s) {
+		return append(dst, s...)
+	}
+	dst = append(dst, '"')
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch r {
+		case '"', '\\':
+			dst = append(dst, '\\', byte(r))
+		case '\n':
+			dst = append(dst, `\n`...)
+		case '\r':
+			dst = append(dst, `\r`...)
+		case '\t':
+			dst = append(dst, `\t`...)
+		default:
+			// Invalid UTF-8 decodes to utf8.RuneError and is written as U+FFFD
+			if strconv.IsPrint(r) {
+				dst = utf8.AppendRune(dst, r)
+			} else if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+				dst = appendUnicodeEscape(dst, r1)
+				dst = appendUnicodeEscape(dst, r2)
+			} else {
+				dst = appendUnicodeEscape(dst, r)
+			}
+		}
+	}
+	return append(dst, '"')
+}
+
+// appendUnicodeEscape writes r, a rune of the basic multilingual plane, as \uXXXX
+func appendUnicodeEscape(dst []byte, r rune) []byte {
+	const hex = "0123456789abcdef"
+	return append(dst, '\\', 'u', hex[r>>12&0xf], hex[r>>8&0xf], hex[r>>4&0xf], hex[r&0xf])
+}
+
+func needsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !strconv.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func valueString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	}
+	return fmt.Sprint(v)
+}
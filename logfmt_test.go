@@ -0,0 +1,185 @@
+package logger
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// parseLogfmt is a minimal logfmt parser used to check the encoder output
+func parseLogfmt(t *testing.T, line string) map[string]string {
+	t.Helper()
+	res := map[string]string{}
+	for line != "" {
+		line = strings.TrimLeft(line, " ")
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 {
+			t.Fatalf("Missing key in %q", line)
+		}
+		key := line[:eq]
+		line = line[eq+1:]
+
+		var val string
+		if strings.HasPrefix(line, `"`) {
+			val, line = unquoteLogfmt(t, line)
+		} else {
+			end := strings.IndexByte(line, ' ')
+			if end < 0 {
+				end = len(line)
+			}
+			val = line[:end]
+			line = line[end:]
+		}
+		res[key] = val
+	}
+	return res
+}
+
+// logfmtEscapes are the escapes logfmt decoders such as go-logfmt accept
+// besides \uXXXX
+var logfmtEscapes = map[byte]rune{'"': '"', '\\': '\\', '/': '/', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t'}
+
+// unquoteLogfmt decodes the quoted value at the start of line and returns it
+// with the rest of the line, like a logfmt decoder rather than strconv would
+func unquoteLogfmt(t *testing.T, line string) (string, string) {
+	t.Helper()
+	var val strings.Builder
+	for i := 1; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '"':
+			return val.String(), line[i+1:]
+		case c < ' ':
+			t.Fatalf("Unescaped control character in %q", line)
+		case c != '\\':
+			val.WriteByte(c)
+		case i+1 < len(line) && line[i+1] == 'u':
+			r := parseHex4(t, line, i+2)
+			i += 5
+			if utf16.IsSurrogate(r) && strings.HasPrefix(line[i+1:], `\u`) {
+				r = utf16.DecodeRune(r, parseHex4(t, line, i+3))
+				i += 6
+			}
+			val.WriteRune(r)
+		default:
+			i++
+			r, ok := rune(0), false
+			if i < len(line) {
+				r, ok = logfmtEscapes[line[i]]
+			}
+			if !ok {
+				t.Fatalf("Escape not accepted by logfmt decoders in %q", line)
+			}
+			val.WriteRune(r)
+		}
+	}
+	t.Fatalf("Unterminated quote in %q", line)
+	return "", ""
+}
+
+func parseHex4(t *testing.T, line string, at int) rune {
+	t.Helper()
+	if at+4 > len(line) {
+		t.Fatalf("Short \\u escape in %q", line)
+	}
+	n, err := strconv.ParseUint(line[at:at+4], 16, 16)
+	if err != nil {
+		t.Fatalf("Bad \\u escape in %q", line)
+	}
+	return rune(n)
+}
+
+func TestLogfmtEncoder_RoundTrip(t *testing.T) {
+	values := []string{
+		"plain",
+		"",
+		"two words",
+		`say "hi"`,
+		"line one\nline two",
+		"tab\there",
+		`back\slash`,
+		"a=b",
+		"unicode ✓ ok",
+		"\x00control",
+		"bell\a vtab\v del\x7f",
+		"nbsp\u00a0 line sep\u2028",
+		"emoji 😀",
+	}
+
+	for _, v := range values {
+		e := &Entry{
+			Time:    time.Date(2025, 5, 23, 10, 4, 5, 0, time.UTC),
+			Level:   LevelInfo,
+			Tag:     "GPIO",
+			Message: v,
+			Fields:  []Field{{Key: "value", Value: v}, {Key: "err", Value: errors.New(v)}},
+		}
+
+		line := string(NewLogfmtEncoder().Encode(nil, e))
+		if strings.ContainsAny(line, "\n\r") {
+			t.Errorf("Expected a single line but got: %q", line)
+		}
+
+		got := parseLogfmt(t, line)
+		if got["msg"] != v || got["value"] != v || got["err"] != v {
+			t.Errorf("Round trip of %q failed, line: %q, parsed: %q", v, line, got)
+		}
+
+		if got["level"] != "info" || got["tag"] != "GPIO" || got["time"] != "2025-05-23T10:04:05.000Z" {
+			t.Errorf("Unexpected header in %q", line)
+		}
+	}
+}
+
+func TestLogfmtEncoder_InvalidUTF8(t *testing.T) {
+	e := &Entry{Level: LevelInfo, Message: "bad \xff byte"}
+
+	got := parseLogfmt(t, string(NewLogfmtEncoder().Encode(nil, e)))
+	if got["msg"] != "bad \uFFFD byte" {
+		t.Errorf("Expected invalid UTF-8 to become U+FFFD but got: %q", got["msg"])
+	}
+}
+
+func TestLogfmtEncoder_Keys(t *testing.T) {
+	e := &Entry{
+		Level:  LevelInfo,
+		Fields: []Field{{Key: "bad key=x", Value: 1}, {Key: "", Value: 2}},
+	}
+
+	got := parseLogfmt(t, string(NewLogfmtEncoder().Encode(nil, e)))
+	if got["bad_key_x"] != "1" || got["_"] != "2" {
+		t.Errorf("Expected sanitized keys but got: %q", got)
+	}
+}
+
+func TestLogfmtEncoder_ReservedKeys(t *testing.T) {
+	e := &Entry{
+		Level:   LevelInfo,
+		Message: "m",
+		Fields:  []Field{{Key: "msg", Value: "x"}, {Key: "level", Value: "y"}},
+	}
+
+	got := parseLogfmt(t, string(NewLogfmtEncoder().Encode(nil, e)))
+	if got["msg"] != "m" || got["level"] != "info" || got["fields.msg"] != "x" || got["fields.level"] != "y" {
+		t.Errorf("Expected namespaced fields but got: %q", got)
+	}
+}
+
+func TestLoggerSync_LogfmtConsole(t *testing.T) {
+	logger := NewSync("GPIO", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetConsoleEncoder(NewLogfmtEncoder())
+
+	logger.With("pin", 17).Info("pin configured")
+
+	output := buf.waitLines(1)
+
+	got := parseLogfmt(t, strings.TrimSpace(output))
+	if got["msg"] != "pin configured" || got["pin"] != "17" || got["tag"] != "GPIO" {
+		t.Errorf("Unexpected logfmt line: %q", output)
+	}
+}
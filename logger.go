@@ -1,15 +1,28 @@
 // package logger provide async non blocking logging for better app performance
 package logger
 
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
 const (
+	traceKey = "[TRACE] "
 	infoKey  = "[INFO ] "
 	warnKey  = "[WARN ] "
 	errorKey = "[ERROR] "
 	debugKey = "[DEBUG] "
 	panicKey = "[PANIC] "
 	fatalKey = "[FATAL] "
+
+	// styleReset ends a styled console line
+	styleReset = "\033[0m"
 )
 
+// SGR codes for the Set*Style methods, SetLevelStyle takes a Style with
+// 256 and 24 bit colors
 const (
 	StyleFontNone      int8 = 0
 	StyleFontBold      int8 = 1
@@ -35,3 +48,126 @@ const (
 	StyleBgWhite       int8 = 47
 	StyleBgDefault     int8 = 49
 )
+
+var errClosed = errors.New("logger is closed")
+
+// Logger is the common interface implemented by LoggerSync and LoggerAsync,
+// so packages can accept either one without caring how it writes.
+type Logger interface {
+	Trace(a ...any)
+	Tracef(format string, a ...any)
+	Info(a ...any)
+	Infof(format string, a ...any)
+	Warn(a ...any)
+	Warnf(format string, a ...any)
+	Error(a ...any)
+	Errorf(format string, a ...any)
+	Debug(a ...any)
+	Debugf(format string, a ...any)
+	Panic(a ...any)
+	Panicf(format string, a ...any)
+	Fatal(a ...any)
+	Fatalf(format string, a ...any)
+
+	// SetLevel changes the minimum level written, it is safe to call while
+	// other goroutines are logging
+	SetLevel(lv Level)
+	GetLevel() Level
+
+	SetCaller(mode CallerMode)
+	SetStackTraceLevel(lv Level)
+	// Helper marks the calling function as a logging helper that is skipped
+	// when the caller is recorded
+	Helper()
+
+	SetTraceStyle(styles ...int8)
+	SetInfoStyle(styles ...int8)
+	SetWarnStyle(styles ...int8)
+	SetErrorStyle(styles ...int8)
+	SetDebugStyle(styles ...int8)
+	SetPanicStyle(styles ...int8)
+	SetFatalStyle(styles ...int8)
+	SetDefaultStyle()
+	SetLevelStyle(lv Level, s Style)
+	LevelStyle(lv Level) Style
+	ResetStyles()
+	SetTheme(t Theme)
+	UseTheme(name string) error
+	SetTagColors(enable bool)
+	SetMarkup(enable bool)
+	SetColorMode(mode ColorMode)
+
+	// With returns a child logger carrying the given key/value pairs
+	With(keyvals ...any) Logger
+
+	SetOutput(writers ...io.Writer)
+	AddSink(s Sink)
+	SetConsoleEncoder(enc Encoder)
+	SetFileEncoder(enc Encoder)
+	SetConsoleLayout(template string) error
+	SetFileLayout(template string) error
+	SetTimeFormat(layout string)
+	SetTimeLocation(loc *time.Location)
+	SetFileLocation(loc *time.Location)
+	SetUTC()
+
+	SetWriteFilesEnable(path string, objectName string)
+	ChangeFileRoutine(hour int, minute int) error
+	SetMaxFileSize(size int64)
+	SetMaxBackups(n int)
+	SetRetention(p RetentionPolicy)
+	RetentionDryRun() (RetentionReport, error)
+	SetCompress(enable bool)
+	SetFileBuffering(b FileBuffering)
+
+	SetFallbackWriter(w io.Writer)
+	// Sync blocks until everything logged so far was written and the log
+	// file was committed to stable storage, the logger stays usable
+	Sync() error
+	// Close writes out everything that is still pending and closes the log
+	// file, it is safe to call more than once
+	Close(ctx context.Context) error
+}
+
+var (
+	_ Logger = (*LoggerSync)(nil)
+	_ Logger = (*LoggerAsync)(nil)
+)
+
+// Options configure the logger returned by New
+type Options struct {
+	// Tag is displayed in every log message, max 7 characters
+	Tag string
+	// Debug enables debug messages, use SetLevel for trace messages
+	Debug bool
+	// Async selects LoggerAsync instead of LoggerSync
+	Async bool
+	// BufferSize is the size of the buffered channel, only used when Async is true
+	BufferSize int
+	// Backpressure selects what happens when the buffer is full, only used
+	// when Async is true
+	Backpressure BackpressurePolicy
+	// Color selects whether console output is styled, see SetColorMode
+	Color ColorMode
+	// TagColors colors the tag from its name, see SetTagColors
+	TagColors bool
+}
+
+// New creates a LoggerSync or a LoggerAsync depending on opts.Async
+// Example:
+// logger := logger.New(logger.Options{Tag: "GPIO", Async: true, BufferSize: 100})
+// logger.SetDefaultStyle()
+// logger.Info("GPIO handler started")
+func New(opts Options) Logger {
+	if opts.Async {
+		l := NewAsync(opts.Tag, opts.BufferSize, opts.Debug)
+		l.SetBackpressure(opts.Backpressure)
+		l.SetColorMode(opts.Color)
+		l.SetTagColors(opts.TagColors)
+		return l
+	}
+	l := NewSync(opts.Tag, opts.Debug)
+	l.SetColorMode(opts.Color)
+	l.SetTagColors(opts.TagColors)
+	return l
+}
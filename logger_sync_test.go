@@ -7,11 +7,14 @@ import (
 
 func TestLoggerSync_Info_Color(t *testing.T) {
 	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
 	logger.SetInfoStyle(StyleFgGreen) // Expect green ANSI color
 
-	output := CaptureLogOutput(func() {
-		logger.Info("Colored info message")
-	})
+	logger.Info("Colored info message")
+
+	output := buf.waitLines(1)
 
 	if !strings.Contains(output, "\033[32m") {
 		t.Errorf("Expected green ANSI escape sequence, but got: %q", output)
@@ -24,11 +27,14 @@ func TestLoggerSync_Info_Color(t *testing.T) {
 
 func TestLoggerSync_Infof_Color(t *testing.T) {
 	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
 	logger.SetInfoStyle(StyleFgGreen)
 
-	output := CaptureLogOutput(func() {
-		logger.Infof("Formatted %s message", "info")
-	})
+	logger.Infof("Formatted %s message", "info")
+
+	output := buf.waitLines(1)
 
 	if !strings.Contains(output, "\033[32m") {
 		t.Errorf("Expected green ANSI escape sequence, but got: %q", output)
@@ -41,11 +47,14 @@ func TestLoggerSync_Infof_Color(t *testing.T) {
 
 func TestLoggerSync_Warn_Color(t *testing.T) {
 	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
 	logger.SetWarnStyle(StyleFgYellow)
 
-	output := CaptureLogOutput(func() {
-		logger.Warn("Colored warning")
-	})
+	logger.Warn("Colored warning")
+
+	output := buf.waitLines(1)
 
 	if !strings.Contains(output, "\033[33m") {
 		t.Errorf("Expected yellow ANSI escape sequence, but got: %q", output)
@@ -54,11 +63,14 @@ func TestLoggerSync_Warn_Color(t *testing.T) {
 
 func TestLoggerSync_Warnf_Color(t *testing.T) {
 	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
 	logger.SetWarnStyle(StyleFgYellow)
 
-	output := CaptureLogOutput(func() {
-		logger.Warnf("Formatted %s warning", "yellow")
-	})
+	logger.Warnf("Formatted %s warning", "yellow")
+
+	output := buf.waitLines(1)
 
 	if !strings.Contains(output, "\033[33m") {
 		t.Errorf("Expected yellow ANSI escape sequence, but got: %q", output)
@@ -71,11 +83,14 @@ func TestLoggerSync_Warnf_Color(t *testing.T) {
 
 func TestLoggerSync_Error_Color(t *testing.T) {
 	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
 	logger.SetErrorStyle(StyleFgRed)
 
-	output := CaptureLogOutput(func() {
-		logger.Error("Colored error")
-	})
+	logger.Error("Colored error")
+
+	output := buf.waitLines(1)
 
 	if !strings.Contains(output, "\033[31m") {
 		t.Errorf("Expected red ANSI escape sequence, but got: %q", output)
@@ -84,11 +99,14 @@ func TestLoggerSync_Error_Color(t *testing.T) {
 
 func TestLoggerSync_Errorf_Color(t *testing.T) {
 	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
 	logger.SetErrorStyle(StyleFgRed)
 
-	output := CaptureLogOutput(func() {
-		logger.Errorf("Formatted %s error", "red")
-	})
+	logger.Errorf("Formatted %s error", "red")
+
+	output := buf.waitLines(1)
 
 	if !strings.Contains(output, "\033[31m") {
 		t.Errorf("Expected red ANSI escape sequence, but got: %q", output)
@@ -101,11 +119,14 @@ func TestLoggerSync_Errorf_Color(t *testing.T) {
 
 func TestLoggerSync_Debug_Color(t *testing.T) {
 	logger := NewSync("TEST", true) // Debug mode enabled
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
 	logger.SetDebugStyle(StyleFgCyan)
 
-	output := CaptureLogOutput(func() {
-		logger.Debug("Colored debug message")
-	})
+	logger.Debug("Colored debug message")
+
+	output := buf.waitLines(1)
 
 	if !strings.Contains(output, "\033[36m") {
 		t.Errorf("Expected cyan ANSI escape sequence, but got: %q", output)
@@ -118,11 +139,14 @@ func TestLoggerSync_Debug_Color(t *testing.T) {
 
 func TestLoggerSync_Debugf_Color(t *testing.T) {
 	logger := NewSync("TEST", true) // Debug mode enabled
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
 	logger.SetDebugStyle(StyleFgCyan)
 
-	output := CaptureLogOutput(func() {
-		logger.Debugf("Formatted %s debug", "cyan")
-	})
+	logger.Debugf("Formatted %s debug", "cyan")
+
+	output := buf.waitLines(1)
 
 	if !strings.Contains(output, "\033[36m") {
 		t.Errorf("Expected cyan ANSI escape sequence, but got: %q", output)
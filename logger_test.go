@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testBuffer is a bytes.Buffer that can be shared with the async writer goroutine
+type testBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *testBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *testBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitLines returns the output once n lines were written or a second passed
+func (b *testBuffer) waitLines(n int) string {
+	deadline := time.Now().Add(time.Second)
+	for {
+		output := b.String()
+		if strings.Count(output, "\n") >= n || time.Now().After(deadline) {
+			return output
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestNew_SelectsImplementation(t *testing.T) {
+	if _, ok := New(Options{Tag: "TEST"}).(*LoggerSync); !ok {
+		t.Errorf("Expected *LoggerSync when Async is false")
+	}
+
+	l := New(Options{Tag: "TEST", Async: true, BufferSize: 10})
+	if _, ok := l.(*LoggerAsync); !ok {
+		t.Errorf("Expected *LoggerAsync when Async is true")
+	}
+}
+
+func TestNew_Info(t *testing.T) {
+	logger := New(Options{Tag: "TEST"})
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetInfoStyle(StyleFgGreen)
+
+	logger.Info("Interface info message")
+
+	output := buf.waitLines(1)
+
+	if !strings.Contains(output, "Interface info message") {
+		t.Errorf("Expected log message but got: %q", output)
+	}
+}
+
+func TestSetOutput_MultipleWriters(t *testing.T) {
+	logger := NewSync("TEST", false)
+	a, b := &testBuffer{}, &testBuffer{}
+	logger.SetOutput(a, b)
+
+	logger.Info("Fan out message")
+
+	if !strings.Contains(a.String(), "Fan out message") || !strings.Contains(b.String(), "Fan out message") {
+		t.Errorf("Expected message in both writers but got: %q and %q", a.String(), b.String())
+	}
+}
+
+func TestAddSink_JSON(t *testing.T) {
+	logger := NewAsync("TEST", 10, false)
+	console, sink := &testBuffer{}, &testBuffer{}
+	logger.SetOutput(console)
+	logger.AddSink(NewWriterSink(sink, NewJSONEncoder()))
+
+	logger.Info("Sink message")
+
+	if output := sink.waitLines(1); !strings.Contains(output, `"msg":"Sink message"`) {
+		t.Errorf("Expected JSON line in sink but got: %q", output)
+	}
+
+	if output := console.waitLines(1); !strings.Contains(output, "[INFO ]") {
+		t.Errorf("Expected text line on console but got: %q", output)
+	}
+}
@@ -0,0 +1,154 @@
+package logger
+
+import "strings"
+
+// maxMarkupDepth limits how deeply markup tags nest, deeper tags are
+// written as text
+const maxMarkupDepth = 8
+
+// markupAttrs are the attribute names accepted in markup tags
+var markupAttrs = map[string]Attr{
+	"b":             AttrBold,
+	"bold":          AttrBold,
+	"faint":         AttrFaint,
+	"dim":           AttrFaint,
+	"i":             AttrItalic,
+	"italic":        AttrItalic,
+	"u":             AttrUnderline,
+	"underline":     AttrUnderline,
+	"blink":         AttrBlink,
+	"reverse":       AttrReverse,
+	"s":             AttrStrikethrough,
+	"strikethrough": AttrStrikethrough,
+}
+
+// SetMarkup enables inline style markup in messages. A tag such as <b>,
+// <red>, <bold cyan> or <bg:blue> styles the text up to the matching </>,
+// tags nest and take colors in the theme file forms. Styled console lines
+// render the markup, log files, structured encoders and plain console
+// output drop it. \< writes a literal <, and text that is not a valid tag is
+// written as is. Use EscapeMarkup for values that must not be interpreted.
+// Example:
+// logger.SetMarkup(true)
+// logger.Infof("device <b><cyan>%s</></> connected", logger.EscapeMarkup(id))
+func (l *core) SetMarkup(enable bool) {
+	l.markup.Store(enable)
+}
+
+// EscapeMarkup returns s with every < escaped, so it is written as is when
+// markup is enabled
+func EscapeMarkup(s string) string {
+	return strings.ReplaceAll(s, "<", `\<`)
+}
+
+// appendMessage appends the message of e, rendering its markup with ls or
+// dropping the markup when ls is nil
+func appendMessage(dst []byte, e *Entry, ls *lineStyle) []byte {
+	if !e.markup {
+		return append(dst, e.Message...)
+	}
+	return appendMarkup(dst, e.Message, ls)
+}
+
+// plainMessage returns the message of e without markup
+func plainMessage(e *Entry) string {
+	if !e.markup || !strings.ContainsAny(e.Message, `<\`) {
+		return e.Message
+	}
+	return string(appendMarkup(nil, e.Message, nil))
+}
+
+// appendMarkup appends msg with its markup tags turned into escapes merged
+// onto the level style of ls, or dropped when ls is nil
+func appendMarkup(dst []byte, msg string, ls *lineStyle) []byte {
+	var stack [maxMarkupDepth]Style
+	depth := 0
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if c == '\\' && i+1 < len(msg) && msg[i+1] == '<' {
+			dst = append(dst, '<')
+			i++
+			continue
+		}
+		end := -1
+		if c == '<' {
+			end = strings.IndexByte(msg[i:], '>')
+		}
+		if end < 0 {
+			dst = append(dst, c)
+			continue
+		}
+
+		tag := msg[i+1 : i+end]
+		if strings.HasPrefix(tag, "/") && depth > 0 && !strings.Contains(tag, " ") {
+			depth--
+			dst = ls.restyle(dst, stack[:depth])
+			i += end
+			continue
+		}
+		s, ok := parseMarkupTag(tag)
+		if !ok || depth == maxMarkupDepth {
+			dst = append(dst, c)
+			continue
+		}
+		if depth > 0 {
+			s = stack[depth-1].merge(s)
+		}
+		stack[depth] = s
+		depth++
+		dst = ls.restyle(dst, stack[:depth])
+		i += end
+	}
+	if depth > 0 {
+		// Unclosed tags end with the message
+		dst = ls.restyle(dst, nil)
+	}
+	return dst
+}
+
+// restyle switches to the innermost style of stack merged onto the level
+// style, with an empty stack back to the level style
+func (ls *lineStyle) restyle(dst []byte, stack []Style) []byte {
+	if ls == nil {
+		return dst
+	}
+	s := ls.base
+	if len(stack) > 0 {
+		s = s.merge(stack[len(stack)-1])
+	}
+	dst = append(dst, styleReset...)
+	return s.appendSGR(dst, ls.profile)
+}
+
+// parseMarkupTag parses the space separated attributes, colors and bg:color
+// backgrounds of a tag
+func parseMarkupTag(tag string) (Style, bool) {
+	var s Style
+	found := false
+	for tag != "" {
+		var token string
+		token, tag, _ = strings.Cut(tag, " ")
+		if token == "" {
+			continue
+		}
+		found = true
+		if a, ok := markupAttrs[strings.ToLower(token)]; ok {
+			s.attrs |= a
+			continue
+		}
+		if bg, ok := strings.CutPrefix(token, "bg:"); ok && bg != "" {
+			c, ok := parseColor(bg)
+			if !ok {
+				return Style{}, false
+			}
+			s.bg = c
+			continue
+		}
+		c, ok := parseColor(token)
+		if !ok {
+			return Style{}, false
+		}
+		s.fg = c
+	}
+	return s, found
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAppendMarkup_Plain(t *testing.T) {
+	tests := map[string]string{
+		"device <b><cyan>A1</></> connected": "device A1 connected",
+		`\<b> is literal`:                    "<b> is literal",
+		"a <html> tag":                       "a <html> tag",
+		"x < y > z":                          "x < y > z",
+		"stray </> close":                    "stray </> close",
+		"<bold bg:#102030 208>styled</>":     "styled",
+		"unclosed <red>tag":                  "unclosed tag",
+	}
+	for msg, want := range tests {
+		if got := string(appendMarkup(nil, msg, nil)); got != want {
+			t.Errorf("%q: Expected %q but got %q", msg, want, got)
+		}
+	}
+}
+
+func TestAppendMarkup_Styled(t *testing.T) {
+	warn := &lineStyle{base: NewStyle().Foreground(ColorYellow), profile: profile16}
+	got := string(appendMarkup(nil, "a <b>B</> c", warn))
+	want := "a \033[0m\033[1;33mB\033[0m\033[33m c"
+	if got != want {
+		t.Errorf("Expected markup merged onto the level style %q but got %q", want, got)
+	}
+
+	plain := &lineStyle{profile: profile16}
+	got = string(appendMarkup(nil, "<red>R<b>RB</>R</>", plain))
+	want = "\033[0m\033[31mR\033[0m\033[1;31mRB\033[0m\033[31mR\033[0m"
+	if got != want {
+		t.Errorf("Expected nested markup %q but got %q", want, got)
+	}
+}
+
+func TestEscapeMarkup(t *testing.T) {
+	s := "<b>not bold</b>"
+	if got := string(appendMarkup(nil, EscapeMarkup(s), nil)); got != s {
+		t.Errorf("Expected %q back but got %q", s, got)
+	}
+}
+
+func TestLoggerSync_Markup(t *testing.T) {
+	dir := t.TempDir()
+	console, structured := &testBuffer{}, &testBuffer{}
+	logger := NewSync("TEST", false)
+	logger.SetOutput(console)
+	logger.SetColorMode(ColorAlways)
+	logger.SetWarnStyle(StyleFgYellow)
+	logger.AddSink(NewWriterSink(structured, NewJSONEncoder()))
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	logger.SetMarkup(true)
+
+	logger.Warn("device <b>A1</> hot")
+	logger.Close(t.Context())
+
+	if !strings.Contains(console.String(), "device \033[0m\033[1;33mA1\033[0m\033[33m hot\033[0m") {
+		t.Errorf("Expected rendered markup on the console but got: %q", console.String())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, fileNameGenerator("OBJ")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(string(data)), ": device A1 hot") {
+		t.Errorf("Expected the markup to be dropped in the file but got: %q", data)
+	}
+	var line map[string]any
+	if err := json.Unmarshal([]byte(structured.String()), &line); err != nil {
+		t.Fatal(err)
+	}
+	if line["msg"] != "device A1 hot" {
+		t.Errorf("Expected the markup to be dropped in JSON but got: %q", line["msg"])
+	}
+}
+
+func TestLoggerSync_Markup_Disabled(t *testing.T) {
+	buf := &testBuffer{}
+	logger := NewSync("TEST", false)
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
+
+	logger.Info("<b>as is</>")
+
+	if !strings.HasSuffix(buf.String(), ": <b>as is</>\n") {
+		t.Errorf("Expected markup to be written as is by default but got: %q", buf.String())
+	}
+}
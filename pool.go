@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxPooledBuffer keeps the buffers grown by an occasional huge line, e.g. one
+// with a stack trace, from being held by the pool forever
+const maxPooledBuffer = 64 << 10
+
+// buffer is a byte slice taken from bufferPool to build a line without
+// allocating, it is returned with free once the line was written
+type buffer struct {
+	b []byte
+}
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		return &buffer{b: make([]byte, 0, 512)}
+	},
+}
+
+func getBuffer() *buffer {
+	return bufferPool.Get().(*buffer)
+}
+
+func (b *buffer) free() {
+	if cap(b.b) > maxPooledBuffer {
+		return
+	}
+	b.b = b.b[:0]
+	bufferPool.Put(b)
+}
+
+var entryPool = sync.Pool{
+	New: func() any {
+		return &Entry{}
+	},
+}
+
+// newEntry takes an entry from the pool, it is handed back with releaseEntry
+// once every sink wrote it
+func (l *core) newEntry(lv Level, msg string, fields []Field) *Entry {
+	tf := l.timeFormat.Load()
+	now := time.Now()
+	if tf.loc != nil {
+		now = now.In(tf.loc)
+	}
+	e := entryPool.Get().(*Entry)
+	*e = Entry{
+		Time:       now,
+		Level:      lv,
+		Tag:        l.tag,
+		Message:    msg,
+		Fields:     fields,
+		timeLayout: tf.layout,
+		markup:     l.markup.Load(),
+	}
+	return e
+}
+
+func releaseEntry(e *Entry) {
+	*e = Entry{}
+	entryPool.Put(e)
+}
+
+// message returns fmt.Sprint(a...) without copying when a is a single string,
+// the most common way to call Info and the other log methods
+func message(a []any) string {
+	if len(a) == 1 {
+		if s, ok := a[0].(string); ok {
+			return s
+		}
+	}
+	return fmt.Sprint(a...)
+}
@@ -0,0 +1,193 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// record is an item of the async queue, either an entry to write or a
+// barrier that is closed once everything queued before it was written.
+// Entries are never modified once queued.
+type record struct {
+	e       *Entry
+	barrier chan struct{}
+}
+
+// queue is a buffered channel of records that applies the backpressure
+// policy when it is full and counts what it drops
+type queue struct {
+	ch chan record
+	// mu is held for reading while pushing, close takes it for writing so
+	// ch is only closed once no push is in flight
+	mu        sync.RWMutex
+	closed    bool
+	closing   chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+	mode      atomic.Int32
+	timeout   atomic.Int64
+	dropped   atomic.Uint64
+	// pending counts the drops not yet reported with a "messages dropped" line
+	pending atomic.Uint64
+	// deferred holds barriers taken out of the buffer by BackpressureDropOldest,
+	// they are released after the next record is written
+	deferredMu sync.Mutex
+	deferred   []chan struct{}
+}
+
+func newQueue(size int) *queue {
+	return &queue{
+		ch:      make(chan record, size),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+func (q *queue) setPolicy(p BackpressurePolicy) {
+	q.timeout.Store(int64(p.Timeout))
+	q.mode.Store(int32(p.Mode))
+}
+
+func (q *queue) drop() {
+	q.dropped.Add(1)
+	q.pending.Add(1)
+}
+
+// push queues e according to the backpressure policy, it returns false
+// without queueing when the queue is closed or closes while push waits
+func (q *queue) push(e *Entry) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return false
+	}
+
+	r := record{e: e}
+	switch BackpressureMode(q.mode.Load()) {
+	case BackpressureDropNewest:
+		select {
+		case q.ch <- r:
+		default:
+			q.drop()
+		}
+	case BackpressureDropOldest:
+		if cap(q.ch) == 0 {
+			// An unbuffered queue holds nothing to discard, spinning until
+			// the writer takes r would block like BackpressureBlock
+			select {
+			case q.ch <- r:
+			default:
+				q.drop()
+			}
+			return true
+		}
+		for {
+			select {
+			case q.ch <- r:
+				return true
+			case <-q.closing:
+				return false
+			default:
+			}
+			select {
+			case old := <-q.ch:
+				if old.barrier != nil {
+					q.deferBarrier(old.barrier)
+				} else {
+					releaseEntry(old.e)
+					q.drop()
+				}
+			default:
+			}
+		}
+	case BackpressureTimeout:
+		select {
+		case q.ch <- r:
+			return true
+		default:
+		}
+		timer := time.NewTimer(time.Duration(q.timeout.Load()))
+		defer timer.Stop()
+		select {
+		case q.ch <- r:
+		case <-timer.C:
+			q.drop()
+		case <-q.closing:
+			return false
+		}
+	default:
+		return q.pushBlocking(r)
+	}
+	return true
+}
+
+// pushBarrier queues a barrier, barriers are never dropped
+func (q *queue) pushBarrier(barrier chan struct{}) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return false
+	}
+	return q.pushBlocking(record{barrier: barrier})
+}
+
+func (q *queue) pushBlocking(r record) bool {
+	select {
+	case q.ch <- r:
+		return true
+	case <-q.closing:
+		return false
+	}
+}
+
+func (q *queue) deferBarrier(barrier chan struct{}) {
+	q.deferredMu.Lock()
+	q.deferred = append(q.deferred, barrier)
+	q.deferredMu.Unlock()
+}
+
+func (q *queue) releaseDeferred() {
+	q.deferredMu.Lock()
+	for _, barrier := range q.deferred {
+		close(barrier)
+	}
+	q.deferred = nil
+	q.deferredMu.Unlock()
+}
+
+// close stops accepting records, wakes up blocked pushes and lets drain
+// finish what is already queued, it is safe to call more than once
+func (q *queue) close() {
+	q.closeOnce.Do(func() {
+		close(q.closing)
+		q.mu.Lock()
+		q.closed = true
+		close(q.ch)
+		q.mu.Unlock()
+	})
+}
+
+// drain passes every entry received to write, each time the buffer runs
+// empty after messages were dropped a warning with their number is written
+func (q *queue) drain(c *core, write func(e *Entry)) {
+	defer close(q.done)
+	defer q.releaseDeferred()
+	for r := range q.ch {
+		if r.barrier != nil {
+			close(r.barrier)
+			continue
+		}
+		write(r.e)
+		releaseEntry(r.e)
+		q.releaseDeferred()
+		if len(q.ch) == 0 {
+			if n := q.pending.Swap(0); n > 0 {
+				e := c.newEntry(LevelWarn, fmt.Sprintf("%d messages dropped", n), nil)
+				write(e)
+				releaseEntry(e)
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordSink keeps the messages it receives
+type recordSink struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (s *recordSink) WriteEntry(e *Entry) error {
+	s.mu.Lock()
+	s.msgs = append(s.msgs, e.Message)
+	s.mu.Unlock()
+	return nil
+}
+
+// messages extracts the message of every text line, dropping the prefix up to the tag
+func messages(output string) []string {
+	var msgs []string
+	for line := range strings.SplitSeq(strings.TrimSpace(output), "\n") {
+		_, msg, _ := strings.Cut(line, "]: ")
+		msgs = append(msgs, strings.TrimSuffix(msg, "\033[0m"))
+	}
+	return msgs
+}
+
+func TestLoggerAsync_Pipeline_Order(t *testing.T) {
+	const goroutines, perGoroutine = 8, 200
+	dir := t.TempDir()
+	logger := NewAsync("TEST", 16, false)
+	console, sink := &testBuffer{}, &recordSink{}
+	logger.SetOutput(console)
+	logger.AddSink(sink)
+	logger.SetWriteFilesEnable(dir, "OBJ")
+
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			child := logger.With("g", g)
+			for i := range perGoroutine {
+				child.Infof("g%d-%03d", g, i)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, fileNameGenerator("OBJ")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := messages(string(data))
+	for i := range file {
+		file[i], _, _ = strings.Cut(file[i], " ")
+	}
+	cons := messages(console.String())
+	for i := range cons {
+		cons[i], _, _ = strings.Cut(cons[i], " ")
+	}
+
+	if len(file) != goroutines*perGoroutine {
+		t.Fatalf("Expected %d lines but got %d", goroutines*perGoroutine, len(file))
+	}
+	if !slices.Equal(file, cons) || !slices.Equal(file, sink.msgs) {
+		t.Fatalf("Expected file, console and sink to see the same order")
+	}
+
+	next := make([]int, goroutines)
+	for _, msg := range file {
+		var g, i int
+		fmt.Sscanf(msg, "g%d-%03d", &g, &i)
+		if i != next[g] {
+			t.Fatalf("Expected g%d-%03d but got %s", g, next[g], msg)
+		}
+		next[g]++
+	}
+}
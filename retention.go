@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// RetentionPolicy limits the log files kept in the directory given to
+// SetWriteFilesEnable, zero values disable the matching limit
+type RetentionPolicy struct {
+	// MaxAge removes files last written longer ago than MaxAge
+	MaxAge time.Duration
+	// MaxFiles keeps at most MaxFiles files, including the current one
+	MaxFiles int
+	// MaxTotalSize keeps the newest files whose sizes add up to MaxTotalSize bytes
+	MaxTotalSize int64
+}
+
+// RetentionReport lists the files removed by a retention run, or the files a
+// dry run would remove
+type RetentionReport struct {
+	Deleted    []string
+	FreedBytes int64
+}
+
+type retainedFile struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+// SetRetention sets the policy applied after every rotation, only files named
+// like the ones this logger creates, YYYY-MM-DD:<object>.txt and their
+// backups, are ever removed
+// Example:
+// logger.SetRetention(logger.RetentionPolicy{MaxAge: 30 * 24 * time.Hour, MaxFiles: 50})
+func (l *core) SetRetention(p RetentionPolicy) {
+	l.file.mu.Lock()
+	l.file.retention = p
+	l.file.mu.Unlock()
+}
+
+// RetentionDryRun reports which files the current policy would remove without
+// touching them
+func (l *core) RetentionDryRun() (RetentionReport, error) {
+	l.file.mu.Lock()
+	defer l.file.mu.Unlock()
+	return l.file.retain(true)
+}
+
+// logFilePattern matches the files created for objectName, with an optional
+// backup index and an optional .gz extension
+func logFilePattern(objectName string) *regexp.Regexp {
+	return regexp.MustCompile(`^\d{4}-\d{2}-\d{2}:` + regexp.QuoteMeta(objectName) + `(\.\d+)?\.txt(\.gz)?$`)
+}
+
+// retain applies the retention policy, the caller holds f.mu
+func (f *logFile) retain(dryRun bool) (RetentionReport, error) {
+	var report RetentionReport
+	if !f.enabled {
+		return report, errors.New("set write files enable first")
+	}
+	p := f.retention
+	if p.MaxAge <= 0 && p.MaxFiles <= 0 && p.MaxTotalSize <= 0 {
+		return report, nil
+	}
+
+	entries, err := os.ReadDir(f.path)
+	if err != nil {
+		return report, err
+	}
+	pattern := logFilePattern(f.objectName)
+	var files []retainedFile
+	for _, e := range entries {
+		// The file currently written is never removed
+		if e.IsDir() || e.Name() == f.fileName || !pattern.MatchString(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, retainedFile{name: e.Name(), size: info.Size(), modTime: info.ModTime()})
+	}
+	// Newest first, so everything past a limit is the oldest
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+
+	keptFiles := 1
+	keptSize := f.size
+	now := time.Now()
+	for _, rf := range files {
+		expired := p.MaxAge > 0 && now.Sub(rf.modTime) > p.MaxAge
+		tooMany := p.MaxFiles > 0 && keptFiles >= p.MaxFiles
+		tooBig := p.MaxTotalSize > 0 && keptSize+rf.size > p.MaxTotalSize
+		if !expired && !tooMany && !tooBig {
+			keptFiles++
+			keptSize += rf.size
+			continue
+		}
+
+		if !dryRun {
+			if err := os.Remove(filepath.Join(f.path, rf.name)); err != nil {
+				continue
+			}
+		}
+		report.Deleted = append(report.Deleted, rf.name)
+		report.FreedBytes += rf.size
+	}
+	return report, nil
+}
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// writeAged creates name in dir with size bytes and a modification time age ago
+func writeAged(t *testing.T, dir string, name string, size int, age time.Duration) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mt := time.Now().Add(-age)
+	if err := os.Chtimes(path, mt, mt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRetention_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	day := 24 * time.Hour
+	writeAged(t, dir, "2025-01-01:OBJ.txt", 10, 40*day)
+	writeAged(t, dir, "2025-01-01:OBJ.1.txt", 10, 41*day)
+	writeAged(t, dir, "2025-02-01:OBJ.txt", 10, 10*day)
+	writeAged(t, dir, "2025-02-02:OBJ.txt", 10, 9*day)
+	writeAged(t, dir, "2025-01-01:OTHER.txt", 10, 40*day)
+	writeAged(t, dir, "notes.txt", 10, 40*day)
+
+	logger := NewSync("TEST", false)
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	logger.SetRetention(RetentionPolicy{MaxAge: 30 * day})
+
+	report, err := logger.RetentionDryRun()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slices.Sort(report.Deleted)
+	want := []string{"2025-01-01:OBJ.1.txt", "2025-01-01:OBJ.txt"}
+	if !slices.Equal(report.Deleted, want) || report.FreedBytes != 20 {
+		t.Errorf("Expected %v to be reported but got %v (%d bytes)", want, report.Deleted, report.FreedBytes)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "2025-01-01:OBJ.txt")); err != nil {
+		t.Errorf("Expected dry run to keep files: %v", err)
+	}
+}
+
+func TestRetention_MaxFilesAndSize(t *testing.T) {
+	dir := t.TempDir()
+	day := 24 * time.Hour
+	writeAged(t, dir, "2025-02-01:OBJ.txt", 100, 3*day)
+	writeAged(t, dir, "2025-02-02:OBJ.txt", 100, 2*day)
+	writeAged(t, dir, "2025-02-03:OBJ.txt", 100, 1*day)
+	writeAged(t, dir, "2025-01-01:OTHER.txt", 100, 40*day)
+
+	logger := NewSync("TEST", false)
+	logger.SetWriteFilesEnable(dir, "OBJ")
+
+	logger.SetRetention(RetentionPolicy{MaxFiles: 3})
+	report, _ := logger.RetentionDryRun()
+	if !slices.Equal(report.Deleted, []string{"2025-02-01:OBJ.txt"}) {
+		t.Errorf("Expected the oldest file beyond 3 files but got %v", report.Deleted)
+	}
+
+	logger.SetRetention(RetentionPolicy{MaxTotalSize: 150})
+	report, _ = logger.RetentionDryRun()
+	if len(report.Deleted) != 2 || slices.Contains(report.Deleted, "2025-02-03:OBJ.txt") {
+		t.Errorf("Expected all but the newest backup to exceed 150 bytes but got %v", report.Deleted)
+	}
+}
+
+func TestRetention_AfterRotation(t *testing.T) {
+	dir := t.TempDir()
+	writeAged(t, dir, "2025-01-01:OBJ.txt", 10, 400*24*time.Hour)
+
+	logger := NewSync("TEST", false)
+	logger.SetOutput(&testBuffer{})
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	logger.SetRetention(RetentionPolicy{MaxAge: 24 * time.Hour})
+	logger.SetMaxFileSize(100)
+
+	for range 5 {
+		logger.Info("rotate me please")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "2025-01-01:OBJ.txt")); !os.IsNotExist(err) {
+		t.Errorf("Expected the expired file to be removed after rotation")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, backupName(fileNameGenerator("OBJ"), 1))); err != nil {
+		t.Errorf("Expected the fresh backup to be kept: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"io"
+	"sync"
+)
+
+// Sink receives every log entry, implement it to forward logs somewhere that
+// is not a plain io.Writer. Entries are reused once WriteEntry returns, a sink
+// that keeps an entry around must copy it.
+type Sink interface {
+	WriteEntry(e *Entry) error
+}
+
+// NewWriterSink returns a Sink writing each entry encoded with enc as one line to w
+// Example:
+// logger.AddSink(logger.NewWriterSink(conn, logger.NewJSONEncoder()))
+func NewWriterSink(w io.Writer, enc Encoder) Sink {
+	return &writerSink{w: w, enc: enc}
+}
+
+type writerSink struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc Encoder
+	buf []byte
+}
+
+func (s *writerSink) WriteEntry(e *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buf = append(s.enc.Encode(s.buf[:0], e), '\n')
+	_, err := s.w.Write(s.buf)
+	return err
+}
+
+// consoleSink writes to w with the console encoder and level styles of the
+// logger, so SetConsoleEncoder and Set*Style apply to every console writer
+type consoleSink struct {
+	mu   sync.Mutex
+	w    io.Writer
+	core *core
+	// auto is whether ColorAuto styles w and profile how many colors it can
+	// show, both are decided once when the sink is created
+	auto    bool
+	profile colorProfile
+}
+
+func newConsoleSink(w io.Writer, c *core) *consoleSink {
+	return &consoleSink{w: w, core: c, auto: autoColor(w), profile: detectProfile()}
+}
+
+func (s *consoleSink) WriteEntry(e *Entry) error {
+	buf := getBuffer()
+	defer buf.free()
+	buf.b = append(s.core.appendConsoleLine(buf.b, e, s.core.colored(s.auto), s.profile), '\n')
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.w.Write(buf.b)
+	return err
+}
@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// entryLogger is implemented by LoggerSync and LoggerAsync, it lets adapters
+// such as the slog handler hand over complete entries
+type entryLogger interface {
+	Logger
+	enabled(lv Level) bool
+	newEntry(lv Level, msg string, fields []Field) *Entry
+	logEntry(e *Entry)
+	loggerFields() []Field
+	callerFromPC(pc uintptr) Caller
+	stackFromPC(lv Level, pc uintptr, fields []Field) string
+}
+
+// NewSlogHandler returns a slog.Handler writing through l, so slog output gets
+// the same styles, tag, sinks and files as the rest of the logger output.
+// slog levels are mapped to the closest of Trace, Debug, Info, Warn and Error,
+// levels below slog.LevelDebug become Trace. Records with a zero time are
+// written without one.
+// Example:
+// slog.SetDefault(slog.New(logger.NewSlogHandler(logger.NewSync("HTTP", false))))
+func NewSlogHandler(l Logger) slog.Handler {
+	if el, ok := l.(entryLogger); ok {
+		return &slogHandler{l: el, fields: el.loggerFields()}
+	}
+	return &slogHandler{l: &loggerAdapter{l}}
+}
+
+type slogHandler struct {
+	l      entryLogger
+	fields []Field
+	prefix string
+}
+
+// loggerAdapter lets the slog handler write through Logger implementations
+// from outside this package using only the exported methods
+type loggerAdapter struct {
+	Logger
+}
+
+func (a *loggerAdapter) enabled(lv Level) bool {
+	return true
+}
+
+func (a *loggerAdapter) newEntry(lv Level, msg string, fields []Field) *Entry {
+	return &Entry{Time: time.Now(), Level: lv, Message: msg, Fields: fields}
+}
+
+func (a *loggerAdapter) logEntry(e *Entry) {
+	keyvals := make([]any, len(e.Fields))
+	for i, f := range e.Fields {
+		keyvals[i] = f
+	}
+	l := a.With(keyvals...)
+	switch e.Level {
+	case LevelTrace:
+		l.Trace(e.Message)
+	case LevelDebug:
+		l.Debug(e.Message)
+	case LevelInfo:
+		l.Info(e.Message)
+	case LevelWarn:
+		l.Warn(e.Message)
+	default:
+		l.Error(e.Message)
+	}
+}
+
+func (a *loggerAdapter) loggerFields() []Field {
+	return nil
+}
+
+func (a *loggerAdapter) callerFromPC(pc uintptr) Caller {
+	return Caller{}
+}
+
+func (a *loggerAdapter) stackFromPC(lv Level, pc uintptr, fields []Field) string {
+	return ""
+}
+
+func slogLevel(lv slog.Level) Level {
+	switch {
+	case lv < slog.LevelDebug:
+		return LevelTrace
+	case lv < slog.LevelInfo:
+		return LevelDebug
+	case lv < slog.LevelWarn:
+		return LevelInfo
+	case lv < slog.LevelError:
+		return LevelWarn
+	}
+	return LevelError
+}
+
+func (h *slogHandler) Enabled(_ context.Context, lv slog.Level) bool {
+	return h.l.enabled(slogLevel(lv))
+}
+
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := make([]Field, len(h.fields), len(h.fields)+r.NumAttrs())
+	copy(fields, h.fields)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, h.prefix, a)
+		return true
+	})
+
+	e := h.l.newEntry(slogLevel(r.Level), r.Message, fields)
+	if r.Time.IsZero() {
+		// A zero time asks handlers to leave the time out
+		e.Time = time.Time{}
+	} else {
+		e.Time = r.Time.In(e.Time.Location())
+	}
+	e.Caller = h.l.callerFromPC(r.PC)
+	e.Stack = h.l.stackFromPC(e.Level, r.PC, fields)
+	h.l.logEntry(e)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]Field, len(h.fields), len(h.fields)+len(attrs))
+	copy(fields, h.fields)
+	for _, a := range attrs {
+		fields = appendAttr(fields, h.prefix, a)
+	}
+	return &slogHandler{l: h.l, fields: fields, prefix: h.prefix}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{l: h.l, fields: h.fields, prefix: h.prefix + name + "."}
+}
+
+// appendAttr flattens a into fields, group members get the group name and a
+// dot as key prefix, e.g. request.id
+func appendAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return fields
+		}
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range attrs {
+			fields = appendAttr(fields, prefix, ga)
+		}
+		return fields
+	}
+
+	var v any
+	switch a.Value.Kind() {
+	case slog.KindTime:
+		v = a.Value.Time().Format(time.RFC3339Nano)
+	default:
+		v = a.Value.Any()
+	}
+	return append(fields, Field{Key: prefix + a.Key, Value: v})
+}
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+)
+
+func TestSlogHandler_TextStyle(t *testing.T) {
+	logger := NewSync("SLOG", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
+	logger.SetWarnStyle(StyleFgYellow)
+
+	slog.New(NewSlogHandler(logger)).Warn("disk almost full", "free", "3%")
+
+	output := buf.waitLines(1)
+	if !strings.Contains(output, "\033[33m") || !strings.Contains(output, warnKey+"[SLOG   ]: disk almost full free=3%") {
+		t.Errorf("Expected styled warn line but got: %q", output)
+	}
+}
+
+func TestSlogHandler_Levels(t *testing.T) {
+	cases := map[slog.Level]Level{
+		slog.LevelDebug - 4: LevelTrace,
+		slog.LevelDebug - 1: LevelTrace,
+		slog.LevelDebug:     LevelDebug,
+		slog.LevelInfo:      LevelInfo,
+		slog.LevelInfo + 2:  LevelInfo,
+		slog.LevelWarn:      LevelWarn,
+		slog.LevelError:     LevelError,
+		slog.LevelError + 4: LevelError,
+	}
+	for in, want := range cases {
+		if got := slogLevel(in); got != want {
+			t.Errorf("Expected %v to map to %v but got %v", in, want, got)
+		}
+	}
+
+	logger := NewSync("SLOG", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	slog.New(NewSlogHandler(logger)).Debug("hidden")
+	if buf.String() != "" {
+		t.Errorf("Expected debug to be disabled but got: %q", buf.String())
+	}
+}
+
+func TestSlogHandler_AttrsAndGroups(t *testing.T) {
+	logger := NewAsync("SLOG", 10, true)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetConsoleEncoder(NewJSONEncoder())
+
+	h := NewSlogHandler(logger.With("device", "ABA11"))
+	l := slog.New(h).With("service", "api").WithGroup("req").With("id", 7)
+	l.Info("handled", "status", 200, slog.Group("user", "name", "aba"), slog.Group("empty"))
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(buf.waitLines(1)), &got); err != nil {
+		t.Fatalf("Expected a JSON line: %v", err)
+	}
+
+	want := map[string]any{
+		"msg":           "handled",
+		"level":         "info",
+		"device":        "ABA11",
+		"service":       "api",
+		"req.id":        float64(7),
+		"req.status":    float64(200),
+		"req.user.name": "aba",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Expected %s=%v but got %v", k, v, got[k])
+		}
+	}
+	if _, ok := got["req.empty"]; ok {
+		t.Errorf("Expected empty groups to be dropped")
+	}
+}
+
+func TestSlogHandler_Conformance(t *testing.T) {
+	logger := NewSync("SLOG", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetConsoleEncoder(NewJSONEncoder())
+
+	results := func() []map[string]any {
+		var ms []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var flat map[string]any
+			if err := json.Unmarshal([]byte(line), &flat); err != nil {
+				t.Fatalf("Expected a JSON line but got %q: %v", line, err)
+			}
+			// Groups are written as dotted key prefixes, slogtest expects
+			// nested objects
+			m := map[string]any{}
+			for k, v := range flat {
+				keys := strings.Split(k, ".")
+				group := m
+				for _, g := range keys[:len(keys)-1] {
+					if _, ok := group[g].(map[string]any); !ok {
+						group[g] = map[string]any{}
+					}
+					group = group[g].(map[string]any)
+				}
+				group[keys[len(keys)-1]] = v
+			}
+			ms = append(ms, m)
+		}
+		return ms
+	}
+	if err := slogtest.TestHandler(NewSlogHandler(logger), results); err != nil {
+		t.Error(err)
+	}
+}
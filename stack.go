@@ -0,0 +1,134 @@
+package logger
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// SetStackTraceLevel captures the goroutine stack for every message at lv or
+// above and writes it as an indented block after the message. When one of the
+// logged values is an error carrying its own stack, e.g. created with
+// github.com/pkg/errors, that stack is used instead. Stack traces are off by
+// default, LevelOff turns them off again.
+// Example:
+// logger.SetStackTraceLevel(logger.LevelError)
+func (l *core) SetStackTraceLevel(lv Level) {
+	l.stackLevel.Store(int32(lv))
+}
+
+// stack returns the stack trace for a message of level lv logged with args,
+// skip counts the frames above the function calling stack
+func (l *core) stack(lv Level, skip int, args []any, fields []Field) string {
+	if int32(lv) < l.stackLevel.Load() {
+		return ""
+	}
+	for _, a := range args {
+		if pcs := errorStack(a); pcs != nil {
+			return formatStack(pcs)
+		}
+	}
+	for _, f := range fields {
+		if pcs := errorStack(f.Value); pcs != nil {
+			return formatStack(pcs)
+		}
+	}
+
+	var pcs [64]uintptr
+	// +2 skips runtime.Callers and this function
+	n := runtime.Callers(skip+2, pcs[:])
+	return formatStack(pcs[:n])
+}
+
+// stackFromPC is stack for adapters such as the slog handler, the frames
+// above pc, which was recorded by the adapter's caller, are dropped
+func (l *core) stackFromPC(lv Level, pc uintptr, fields []Field) string {
+	if int32(lv) < l.stackLevel.Load() {
+		return ""
+	}
+	for _, f := range fields {
+		if pcs := errorStack(f.Value); pcs != nil {
+			return formatStack(pcs)
+		}
+	}
+
+	var pcs [64]uintptr
+	n := runtime.Callers(2, pcs[:])
+	for i := range n {
+		if pcs[i] == pc {
+			return formatStack(pcs[i:n])
+		}
+	}
+	return formatStack(pcs[:n])
+}
+
+// errorStack returns the program counters recorded by the innermost error in
+// the chain of v that has a StackTrace method returning a slice of program
+// counters, which matches github.com/pkg/errors without depending on it
+func errorStack(v any) []uintptr {
+	err, ok := v.(error)
+	if !ok {
+		return nil
+	}
+	var pcs []uintptr
+	for ; err != nil; err = errors.Unwrap(err) {
+		if found := stackOf(err); found != nil {
+			pcs = found
+		}
+	}
+	return pcs
+}
+
+func stackOf(err error) []uintptr {
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil
+	}
+	out := m.Type().Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	st := m.Call(nil)[0]
+	pcs := make([]uintptr, st.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(st.Index(i).Uint())
+	}
+	return pcs
+}
+
+// formatStack renders pcs like runtime/debug.Stack, one function per line
+// followed by its file and line indented with a tab
+func formatStack(pcs []uintptr) string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			if b.Len() > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString(frame.Function)
+			b.WriteString("\n\t")
+			b.WriteString(frame.File)
+			b.WriteByte(':')
+			b.WriteString(strconv.Itoa(frame.Line))
+		}
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// appendIndented appends s after a newline with every line indented by four
+// spaces, used by the text encoder to write the stack below the message
+func appendIndented(dst []byte, s string) []byte {
+	for line := range strings.SplitSeq(s, "\n") {
+		dst = append(dst, "\n    "...)
+		dst = append(dst, line...)
+	}
+	return dst
+}
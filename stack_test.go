@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// stackError mimics github.com/pkg/errors, its stack is a slice of a named
+// uintptr type
+type frame uintptr
+
+type stackError struct {
+	msg   string
+	stack []frame
+}
+
+func (e *stackError) Error() string { return e.msg }
+
+func (e *stackError) StackTrace() []frame { return e.stack }
+
+func newStackError(msg string) error {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+	st := make([]frame, n)
+	for i := range n {
+		st[i] = frame(pcs[i])
+	}
+	return &stackError{msg: msg, stack: st}
+}
+
+func createdHere() error {
+	return newStackError("from createdHere")
+}
+
+func TestLoggerSync_StackTrace_Threshold(t *testing.T) {
+	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetStackTraceLevel(LevelError)
+
+	logger.Warn("no stack")
+	logger.Error("with stack")
+
+	lines := strings.Split(buf.waitLines(3), "\n")
+	if !strings.HasSuffix(lines[0], "no stack") {
+		t.Errorf("Expected warn without stack but got: %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "with stack") {
+		t.Errorf("Expected the message before the stack but got: %q", lines[1])
+	}
+	if lines[2] != "    github.com/ABA-Developer/go-logger.TestLoggerSync_StackTrace_Threshold" {
+		t.Errorf("Expected the indented test function on top of the stack but got: %q", lines[2])
+	}
+	if !strings.HasPrefix(lines[3], "    \t") || !strings.Contains(lines[3], "stack_test.go:") {
+		t.Errorf("Expected the indented file and line but got: %q", lines[3])
+	}
+}
+
+func TestLoggerAsync_StackTrace_ErrorStack(t *testing.T) {
+	logger := NewAsync("TEST", 10, false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetConsoleEncoder(NewJSONEncoder())
+	logger.SetStackTraceLevel(LevelError)
+
+	err := fmt.Errorf("wrapped: %w", createdHere())
+	logger.Error("request failed: ", err)
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(buf.waitLines(1)), &got); err != nil {
+		t.Fatalf("Expected a JSON line: %v", err)
+	}
+	stack, _ := got["stack"].(string)
+	if !strings.HasPrefix(stack, "github.com/ABA-Developer/go-logger.createdHere\n") {
+		t.Errorf("Expected the error's own stack but got: %q", stack)
+	}
+}
+
+func TestLoggerSync_StackTrace_Off(t *testing.T) {
+	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+
+	logger.Error(errors.New("plain"))
+
+	if output := buf.waitLines(1); strings.Count(output, "\n") != 1 {
+		t.Errorf("Expected no stack by default but got: %q", output)
+	}
+}
+
+func TestSlogHandler_StackTrace(t *testing.T) {
+	logger := NewSync("TEST", false)
+	buf := &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetStackTraceLevel(LevelError)
+
+	slog.New(NewSlogHandler(logger)).Error("slog failure")
+
+	lines := strings.Split(buf.waitLines(2), "\n")
+	if len(lines) < 3 || lines[1] != "    github.com/ABA-Developer/go-logger.TestSlogHandler_StackTrace" {
+		t.Errorf("Expected the stack to start at the slog call site but got: %q", lines)
+	}
+}
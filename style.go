@@ -0,0 +1,462 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Color is a foreground or background color of a Style, the zero value
+// leaves the color of the terminal unchanged
+type Color struct {
+	kind  colorKind
+	value uint32
+}
+
+type colorKind uint8
+
+const (
+	colorNone colorKind = iota
+	colorDefault
+	colorANSI
+	color256
+	colorRGB
+)
+
+// The 8 basic colors, ANSI(8) to ANSI(15) are their bright variants
+var (
+	ColorBlack   = ANSI(0)
+	ColorRed     = ANSI(1)
+	ColorGreen   = ANSI(2)
+	ColorYellow  = ANSI(3)
+	ColorBlue    = ANSI(4)
+	ColorMagenta = ANSI(5)
+	ColorCyan    = ANSI(6)
+	ColorWhite   = ANSI(7)
+	// ColorDefault selects the default color of the terminal
+	ColorDefault = Color{kind: colorDefault}
+)
+
+// ANSI returns one of the 16 basic terminal colors, 0 to 7 are the normal
+// and 8 to 15 the bright ones, larger values are reduced modulo 16
+func ANSI(n uint8) Color {
+	return Color{kind: colorANSI, value: uint32(n % 16)}
+}
+
+// Color256 returns a color of the xterm 256 color palette
+func Color256(n uint8) Color {
+	return Color{kind: color256, value: uint32(n)}
+}
+
+// RGB returns a 24 bit color, terminals without truecolor support get the
+// closest color they can show
+func RGB(r, g, b uint8) Color {
+	return Color{kind: colorRGB, value: uint32(r)<<16 | uint32(g)<<8 | uint32(b)}
+}
+
+// IsZero reports whether c leaves the terminal color unchanged
+func (c Color) IsZero() bool {
+	return c.kind == colorNone
+}
+
+// Attr is a text attribute of a Style, attributes are combined with |
+type Attr uint16
+
+const (
+	AttrBold Attr = 1 << iota
+	AttrFaint
+	AttrItalic
+	AttrUnderline
+	AttrBlink
+	AttrReverse
+	AttrStrikethrough
+)
+
+// attrCodes are the SGR codes of the attributes in bit order
+var attrCodes = [...]int{1, 2, 3, 4, 5, 7, 9}
+
+// Style is how a console line or part of it is rendered. Styles are values,
+// the methods return a modified copy.
+// Example:
+// s := logger.NewStyle(logger.AttrBold).Foreground(logger.RGB(255, 136, 0))
+type Style struct {
+	fg    Color
+	bg    Color
+	attrs Attr
+}
+
+// NewStyle returns a style with the given attributes and no colors
+func NewStyle(attrs ...Attr) Style {
+	var s Style
+	for _, a := range attrs {
+		s.attrs |= a
+	}
+	return s
+}
+
+// Foreground returns s with the text color c
+func (s Style) Foreground(c Color) Style {
+	s.fg = c
+	return s
+}
+
+// Background returns s with the background color c
+func (s Style) Background(c Color) Style {
+	s.bg = c
+	return s
+}
+
+// With returns s with the attributes a added
+func (s Style) With(a Attr) Style {
+	s.attrs |= a
+	return s
+}
+
+// IsZero reports whether s leaves the text unstyled
+func (s Style) IsZero() bool {
+	return s == Style{}
+}
+
+// StyleFromCodes converts the Style* SGR codes, e.g. StyleFgRed, into a
+// Style. Codes without a typed equivalent are ignored.
+func StyleFromCodes(codes ...int8) Style {
+	var s Style
+	for _, code := range codes {
+		switch c := int(code); {
+		case c >= 30 && c <= 37:
+			s.fg = ANSI(uint8(c - 30))
+		case c == 39:
+			s.fg = ColorDefault
+		case c >= 40 && c <= 47:
+			s.bg = ANSI(uint8(c - 40))
+		case c == 49:
+			s.bg = ColorDefault
+		case c >= 90 && c <= 97:
+			s.fg = ANSI(uint8(c - 90 + 8))
+		case c >= 100 && c <= 107:
+			s.bg = ANSI(uint8(c - 100 + 8))
+		default:
+			for i, ac := range attrCodes {
+				if ac == c {
+					s.attrs |= 1 << i
+				}
+			}
+		}
+	}
+	return s
+}
+
+// colorProfile is how many colors a terminal can show
+type colorProfile int8
+
+const (
+	profile16 colorProfile = iota
+	profile256
+	profileTrueColor
+	profileCount
+)
+
+// detectProfile reads the COLORTERM and TERM conventions
+func detectProfile() colorProfile {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return profileTrueColor
+	}
+	term := os.Getenv("TERM")
+	switch {
+	case strings.HasSuffix(term, "-direct"):
+		return profileTrueColor
+	case strings.Contains(term, "256color"):
+		return profile256
+	}
+	return profile16
+}
+
+// sgr returns the escape sequence selecting s for a terminal showing p
+// colors, an empty string when s is the zero style
+func (s Style) sgr(p colorProfile) string {
+	return string(s.appendSGR(nil, p))
+}
+
+// appendSGR appends the escape sequence selecting s, nothing for the zero style
+func (s Style) appendSGR(dst []byte, p colorProfile) []byte {
+	if s.IsZero() {
+		return dst
+	}
+	dst = append(dst, "\033["...)
+	n := 0
+	for i, code := range attrCodes {
+		if s.attrs&(1<<i) != 0 {
+			if n > 0 {
+				dst = append(dst, ';')
+			}
+			dst = strconv.AppendInt(dst, int64(code), 10)
+			n++
+		}
+	}
+	if !s.fg.IsZero() {
+		if n > 0 {
+			dst = append(dst, ';')
+		}
+		dst = s.fg.appendSGR(dst, p, 30)
+		n++
+	}
+	if !s.bg.IsZero() {
+		if n > 0 {
+			dst = append(dst, ';')
+		}
+		dst = s.bg.appendSGR(dst, p, 40)
+	}
+	return append(dst, 'm')
+}
+
+// merge returns s with the attributes of o added and the colors o sets
+func (s Style) merge(o Style) Style {
+	s.attrs |= o.attrs
+	if !o.fg.IsZero() {
+		s.fg = o.fg
+	}
+	if !o.bg.IsZero() {
+		s.bg = o.bg
+	}
+	return s
+}
+
+// appendSGR appends the codes selecting c, base is 30 for the foreground and
+// 40 for the background
+func (c Color) appendSGR(dst []byte, p colorProfile, base int) []byte {
+	switch c.kind {
+	case colorDefault:
+		return strconv.AppendInt(dst, int64(base+9), 10)
+	case color256:
+		if p < profile256 {
+			return ANSI(nearestANSI(palette256(uint8(c.value)))).appendSGR(dst, p, base)
+		}
+		dst = strconv.AppendInt(dst, int64(base+8), 10)
+		dst = append(dst, ";5;"...)
+		return strconv.AppendInt(dst, int64(c.value), 10)
+	case colorRGB:
+		switch p {
+		case profile16:
+			return ANSI(nearestANSI(c.value)).appendSGR(dst, p, base)
+		case profile256:
+			return Color256(nearest256(c.value)).appendSGR(dst, p, base)
+		}
+		dst = strconv.AppendInt(dst, int64(base+8), 10)
+		dst = append(dst, ";2;"...)
+		dst = strconv.AppendInt(dst, int64(c.value>>16), 10)
+		dst = append(dst, ';')
+		dst = strconv.AppendInt(dst, int64(c.value>>8&0xff), 10)
+		dst = append(dst, ';')
+		return strconv.AppendInt(dst, int64(c.value&0xff), 10)
+	}
+	// colorANSI, bright colors use 90-97 and 100-107
+	if c.value >= 8 {
+		return strconv.AppendInt(dst, int64(base+60+int(c.value)-8), 10)
+	}
+	return strconv.AppendInt(dst, int64(base+int(c.value)), 10)
+}
+
+// ansiPalette is the xterm rendering of the 16 basic colors
+var ansiPalette = [16]uint32{
+	0x000000, 0xcd0000, 0x00cd00, 0xcdcd00, 0x0000ee, 0xcd00cd, 0x00cdcd, 0xe5e5e5,
+	0x7f7f7f, 0xff0000, 0x00ff00, 0xffff00, 0x5c5cff, 0xff00ff, 0x00ffff, 0xffffff,
+}
+
+// cubeLevels are the channel values of the 6x6x6 color cube of the 256 palette
+var cubeLevels = [6]uint32{0, 95, 135, 175, 215, 255}
+
+// palette256 returns the RGB value of a color of the 256 color palette
+func palette256(n uint8) uint32 {
+	switch {
+	case n < 16:
+		return ansiPalette[n]
+	case n < 232:
+		i := uint32(n - 16)
+		return cubeLevels[i/36]<<16 | cubeLevels[i/6%6]<<8 | cubeLevels[i%6]
+	}
+	gray := 8 + 10*uint32(n-232)
+	return gray<<16 | gray<<8 | gray
+}
+
+// nearest256 returns the color of the cube or the gray ramp closest to rgb
+func nearest256(rgb uint32) uint8 {
+	best, bestDist := uint8(16), uint32(1<<31)
+	for n := 16; n < 256; n++ {
+		if d := rgbDistance(rgb, palette256(uint8(n))); d < bestDist {
+			best, bestDist = uint8(n), d
+		}
+	}
+	return best
+}
+
+// nearestANSI returns the basic color closest to rgb
+func nearestANSI(rgb uint32) uint8 {
+	best, bestDist := uint8(0), uint32(1<<31)
+	for n, c := range ansiPalette {
+		if d := rgbDistance(rgb, c); d < bestDist {
+			best, bestDist = uint8(n), d
+		}
+	}
+	return best
+}
+
+func rgbDistance(a, b uint32) uint32 {
+	dr := int32(a>>16) - int32(b>>16)
+	dg := int32(a>>8&0xff) - int32(b>>8&0xff)
+	db := int32(a&0xff) - int32(b&0xff)
+	return uint32(dr*dr + dg*dg + db*db)
+}
+
+// stylePart is a part of a text line that is styled on its own
+type stylePart int8
+
+const (
+	partTime stylePart = iota
+	partTag
+	partKey
+	partCount
+)
+
+// lineStyle holds the escape prefixes a console line of one level is
+// written with on one color profile
+type lineStyle struct {
+	level string
+	parts [partCount]string
+	// base is the level style and profile the terminal profile, markup
+	// styles are merged onto base
+	base    Style
+	profile colorProfile
+}
+
+// styledEncoder is implemented by the text encoders, they style the parts of
+// a line themselves when writing to a styled console
+type styledEncoder interface {
+	Encoder
+	encodeStyled(dst []byte, e *Entry, ls *lineStyle) []byte
+}
+
+// open starts the style of part, a nil lineStyle writes plain text
+func (ls *lineStyle) open(dst []byte, part stylePart) []byte {
+	if ls == nil || ls.parts[part] == "" {
+		return dst
+	}
+	return append(dst, ls.parts[part]...)
+}
+
+// close ends the style of part and restores the level style
+func (ls *lineStyle) close(dst []byte, part stylePart) []byte {
+	if ls == nil || ls.parts[part] == "" {
+		return dst
+	}
+	dst = append(dst, styleReset...)
+	return append(dst, ls.level...)
+}
+
+// colorNames are the names of the 8 basic colors, the bright variants are
+// written with a bright- prefix
+var colorNames = [...]string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// attrNames are the names of the attributes in bit order
+var attrNames = [...]string{"bold", "faint", "italic", "underline", "blink", "reverse", "strikethrough"}
+
+// MarshalText writes c as a color name such as red or bright-red, default,
+// a 256 color palette index or #rrggbb
+func (c Color) MarshalText() ([]byte, error) {
+	switch c.kind {
+	case colorNone:
+		return nil, nil
+	case colorDefault:
+		return []byte("default"), nil
+	case color256:
+		return strconv.AppendInt(nil, int64(c.value), 10), nil
+	case colorRGB:
+		return fmt.Appendf(nil, "#%06x", c.value), nil
+	}
+	if c.value >= 8 {
+		return []byte("bright-" + colorNames[c.value-8]), nil
+	}
+	return []byte(colorNames[c.value]), nil
+}
+
+// UnmarshalText reads the forms written by MarshalText
+func (c *Color) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" {
+		*c = Color{}
+		return nil
+	}
+	color, ok := parseColor(s)
+	if !ok {
+		return fmt.Errorf("unknown color %q, want a name, a palette index or #rrggbb", s)
+	}
+	*c = color
+	return nil
+}
+
+// parseColor parses a non-empty color in the forms written by MarshalText
+func parseColor(s string) (Color, bool) {
+	s = strings.ToLower(s)
+	switch {
+	case s == "default":
+		return ColorDefault, true
+	case strings.HasPrefix(s, "#"):
+		v, err := strconv.ParseUint(s[1:], 16, 32)
+		if err != nil || len(s) != 7 {
+			return Color{}, false
+		}
+		return RGB(uint8(v>>16), uint8(v>>8), uint8(v)), true
+	}
+	if n, err := strconv.ParseUint(s, 10, 8); err == nil {
+		return Color256(uint8(n)), true
+	}
+	name, bright := strings.CutPrefix(s, "bright-")
+	for i, cn := range colorNames {
+		if cn == name {
+			if bright {
+				i += 8
+			}
+			return ANSI(uint8(i)), true
+		}
+	}
+	return Color{}, false
+}
+
+// styleJSON is the form of a Style in theme files
+// {"fg": "#ff8800", "bg": "blue", "attrs": ["bold", "underline"]}
+type styleJSON struct {
+	Fg    Color    `json:"fg,omitzero"`
+	Bg    Color    `json:"bg,omitzero"`
+	Attrs []string `json:"attrs,omitempty"`
+}
+
+// MarshalJSON writes s in the form used by theme files
+func (s Style) MarshalJSON() ([]byte, error) {
+	v := styleJSON{Fg: s.fg, Bg: s.bg}
+	for i, name := range attrNames {
+		if s.attrs&(1<<i) != 0 {
+			v.Attrs = append(v.Attrs, name)
+		}
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON reads a style in the form used by theme files
+func (s *Style) UnmarshalJSON(data []byte) error {
+	var v styleJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = Style{fg: v.Fg, bg: v.Bg}
+	for _, a := range v.Attrs {
+		i := slices.Index(attrNames[:], strings.ToLower(a))
+		if i < 0 {
+			return fmt.Errorf("unknown style attribute %q", a)
+		}
+		s.attrs |= 1 << i
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestStyle_SGR(t *testing.T) {
+	orange := NewStyle(AttrBold).Foreground(RGB(255, 136, 0))
+	tests := []struct {
+		style   Style
+		profile colorProfile
+		want    string
+	}{
+		{Style{}, profileTrueColor, ""},
+		{orange, profileTrueColor, "\033[1;38;2;255;136;0m"},
+		{orange, profile256, "\033[1;38;5;208m"},
+		{orange, profile16, "\033[1;33m"},
+		{NewStyle().Background(Color256(208)), profile256, "\033[48;5;208m"},
+		{NewStyle().Background(Color256(196)), profile16, "\033[101m"},
+		{NewStyle().Foreground(ANSI(9)).Background(ColorDefault), profile16, "\033[91;49m"},
+		{NewStyle(AttrBlink, AttrStrikethrough).With(AttrReverse), profile16, "\033[5;7;9m"},
+		{StyleFromCodes(StyleFontBold, StyleFgBlack, StyleBgMagenta), profile16, "\033[1;30;45m"},
+	}
+	for _, tt := range tests {
+		if got := tt.style.sgr(tt.profile); got != tt.want {
+			t.Errorf("Expected %q but got %q", tt.want, got)
+		}
+	}
+}
+
+func TestNearest256_Gray(t *testing.T) {
+	if got := nearest256(0x808080); got != 244 {
+		t.Errorf("Expected gray 244 but got %d", got)
+	}
+}
+
+func TestLoggerSync_SetStyle_Replaces(t *testing.T) {
+	buf := &testBuffer{}
+	logger := NewSync("TEST", false)
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
+
+	logger.SetInfoStyle(StyleFgRed)
+	logger.SetInfoStyle(StyleFgGreen)
+	logger.SetDefaultStyle()
+	logger.SetDefaultStyle()
+	logger.Info("once")
+
+	if !strings.HasPrefix(buf.String(), "\033[37m[") {
+		t.Errorf("Expected only the default info style but got: %q", buf.String())
+	}
+
+	buf = &testBuffer{}
+	logger.SetOutput(buf)
+	logger.ResetStyles()
+	logger.Info("plain")
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("Expected no escapes after ResetStyles but got: %q", buf.String())
+	}
+}
+
+func TestLoggerSync_SetLevelStyle_Downgrade(t *testing.T) {
+	t.Setenv("COLORTERM", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	buf := &testBuffer{}
+	logger := NewSync("TEST", false)
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
+	logger.SetLevelStyle(LevelWarn, NewStyle().Foreground(RGB(255, 136, 0)))
+
+	logger.Warn("orange")
+
+	if !strings.HasPrefix(buf.String(), "\033[38;5;208m") {
+		t.Errorf("Expected the 256 color fallback but got: %q", buf.String())
+	}
+	if got := logger.LevelStyle(LevelWarn); got != NewStyle().Foreground(RGB(255, 136, 0)) {
+		t.Errorf("Expected the style that was set but got %+v", got)
+	}
+}
+
+func TestLoggerAsync_SetStyle_Concurrent(t *testing.T) {
+	logger := NewAsync("TEST", 10, false)
+	logger.SetOutput(&testBuffer{})
+	logger.SetColorMode(ColorAlways)
+	defer logger.Close(t.Context())
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range 100 {
+			logger.SetDefaultStyle()
+			logger.ResetStyles()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := range 100 {
+			logger.Info(i)
+		}
+	}()
+	wg.Wait()
+}
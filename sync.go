@@ -1,33 +1,19 @@
 package logger
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"log"
 	"os"
-	"strings"
-	"time"
 )
 
 type LoggerSync struct {
-	tag             string
-	enDebug         bool
-	infoStyle       []int8
-	warnStyle       []int8
-	errorStyle      []int8
-	debugStyle      []int8
-	panicStyle      []int8
-	fatalStyle      []int8
-	writeFileEnable bool
-	objectName      string
-	file            *os.File
-	fileName        string
-	path            string
+	*core
+	fields []Field
 }
 
 // New creates a new Logger instance
 // tag: a string that will be displayed in the log message, max 7 characters
-// debugMode: if true, debug messages will be displayed
+// debugMode: if true, debug messages will be displayed, see SetLevel
 // returns a pointer to the Logger instance
 // Example:
 // logger := logger.New("GPIO", true)
@@ -36,212 +22,161 @@ type LoggerSync struct {
 // log format: [INFO] [TIME] [GPIO]: GPIO handler started
 
 func NewSync(tag string, debugMode bool) *LoggerSync {
-	lenTag := len(tag)
-	if lenTag < 7 {
-		tag += strings.Repeat(" ", 7-lenTag)
-	}
-	tag = "[" + tag + "]"
-
 	// Create a new LoggerSync instance
 	logger := &LoggerSync{
-		tag:             tag,
-		enDebug:         debugMode,
-		writeFileEnable: false,
+		core: newCore(tag, debugMode),
 	}
-	log.SetOutput(os.Stdout)
-	log.SetFlags(0) // Disable the default timestamp and log prefix
 
 	return logger
 }
 
-func (l *LoggerSync) ChangeFileRoutine(hour int, minute int) error {
-	if !l.writeFileEnable {
-		return errors.New("set write files enable first")
+// With returns a child logger that appends the given key/value pairs to every
+// line it writes, the child shares styles and files with its parent
+// Example:
+// gpio := logger.With("pin", 17)
+// gpio.Info("pin configured")
+// log format: [INFO] [TIME] [GPIO]: pin configured pin=17
+func (l *LoggerSync) With(keyvals ...any) Logger {
+	return &LoggerSync{
+		core:   l.core,
+		fields: appendFields(l.fields, keyvals...),
 	}
-	HOUR := hour
-	MINUTE := minute
-	go func() {
-		for range time.Tick(1 * time.Minute) {
-			hours, minutes, _ := time.Now().Clock()
-			if hours == HOUR && minutes == MINUTE {
-				// Close first previous object file
-				l.file.Close()
-
-				// Create new file object with the append mode
-				l.fileName = fileNameGenerator(l.objectName)
-				l.file = createAndAppendObject(l.fileName, l.path)
-			}
-		}
-	}()
-	return nil
 }
 
-func (l *LoggerSync) writeLog(msg string) {
-	if l.writeFileEnable {
-		l.file.WriteString(msg + "\n")
-	}
+func (l *LoggerSync) loggerFields() []Field {
+	return l.fields
 }
 
-func (l *LoggerSync) SetWriteFilesEnable(path string, objectName string) {
-	// Initial file object
-	l.objectName = objectName
-	l.path = newFolderPath(path)
-	l.fileName = fileNameGenerator(l.objectName)
-	l.file = createAndAppendObject(l.fileName, path)
-	l.writeFileEnable = true
+// Flush commits the current log file to stable storage, the console is
+// written synchronously so there is nothing else to wait for
+func (l *LoggerSync) Flush() {
+	l.file.sync()
 }
 
-func (l *LoggerSync) applyStyle(str string, styles ...int8) string {
-	resStr := "\033["
-
-	for i := range len(styles) {
-		if i == 0 {
-			resStr += fmt.Sprintf("%d", styles[i])
-		} else {
-			resStr += fmt.Sprintf(";%d", styles[i])
-		}
+// Sync commits the current log file to stable storage, like Flush
+func (l *LoggerSync) Sync() error {
+	if l.closed.Load() {
+		return errClosed
 	}
-	resStr += fmt.Sprintf("m%s\033[0m", str)
-
-	return resStr
-}
-
-func (l *LoggerSync) getTime() string {
-	return "[" + time.Now().Format("2006-01-02 15:04:05.000") + "] "
-}
-
-func (l *LoggerSync) SetInfoStyle(styles ...int8) {
-	for i := range len(styles) {
-		l.infoStyle = append(l.infoStyle, styles[i])
+	return l.file.sync()
+}
+
+// Close commits and closes the log file and waits for the compression of
+// rotated files, it can be called more than once. When ctx is done before the
+// compression finishes, ctx.Err() is returned. Messages logged after Close go
+// to the fallback writer, if one is set.
+func (l *LoggerSync) Close(ctx context.Context) error {
+	l.shutdown()
+	err := l.file.close()
+	if werr := l.file.waitCompress(ctx); err == nil {
+		err = werr
 	}
+	return err
 }
 
-func (l *LoggerSync) SetWarnStyle(styles ...int8) {
-	for i := range len(styles) {
-		l.warnStyle = append(l.warnStyle, styles[i])
-	}
+func (l *LoggerSync) log(lv Level, msg string, args []any) {
+	e := l.newEntry(lv, msg, l.fields)
+	// Skip log and the exported method calling it
+	e.Caller = l.caller(2)
+	e.Stack = l.stack(lv, 2, args, l.fields)
+	l.logEntry(e)
 }
 
-func (l *LoggerSync) SetErrorStyle(styles ...int8) {
-	for i := range len(styles) {
-		l.errorStyle = append(l.errorStyle, styles[i])
+func (l *LoggerSync) logEntry(e *Entry) {
+	if l.closed.Load() {
+		l.writeFallback(e)
+	} else {
+		l.dispatch(e)
 	}
-}
-
-func (l *LoggerSync) SetDebugStyle(styles ...int8) {
-	for i := range len(styles) {
-		l.debugStyle = append(l.debugStyle, styles[i])
+	lv, msg := e.Level, e.Message
+	releaseEntry(e)
+	switch lv {
+	case LevelPanic:
+		// An unrecovered panic ends the process, the line must not stay in
+		// the file buffer
+		l.file.sync()
+		panic(msg)
+	case LevelFatal:
+		l.Flush()
+		os.Exit(1)
 	}
 }
 
-func (l *LoggerSync) SetPanicStyle(styles ...int8) {
-	for i := range len(styles) {
-		l.panicStyle = append(l.panicStyle, styles[i])
-	}
-}
+// LOG FORMAT
+// [TIME] [INFO ] [TAG]: message key=value
 
-func (l *LoggerSync) SetFatalStyle(styles ...int8) {
-	for i := range len(styles) {
-		l.fatalStyle = append(l.fatalStyle, styles[i])
+func (l *LoggerSync) Trace(a ...any) {
+	if l.enabled(LevelTrace) {
+		l.log(LevelTrace, message(a), a)
 	}
 }
 
-func (l *LoggerSync) SetDefaultStyle() {
-	l.SetInfoStyle(StyleFgWhite)
-	l.SetWarnStyle(StyleFgYellow)
-	l.SetErrorStyle(StyleFgRed)
-	l.SetDebugStyle(StyleFontItalic, StyleFontComment)
-	l.SetPanicStyle(StyleFontBold, StyleFgBlack, StyleBgMagenta)
-	l.SetFatalStyle(StyleFontBold, StyleFgBlack, StyleBgRed)
+func (l *LoggerSync) Tracef(format string, a ...any) {
+	if l.enabled(LevelTrace) {
+		l.log(LevelTrace, fmt.Sprintf(format, a...), a)
+	}
 }
 
-// LOG FORMAT
-// [INFO] [TIME] [TAG]: message
-
 func (l *LoggerSync) Info(a ...any) {
-	msg := l.getTime() + infoKey + l.tag + ": " + fmt.Sprint(a...)
-	l.writeLog(msg)
-	msg = l.applyStyle(msg, l.infoStyle...)
-	log.Println(msg)
+	if l.enabled(LevelInfo) {
+		l.log(LevelInfo, message(a), a)
+	}
 }
 
 func (l *LoggerSync) Infof(format string, a ...any) {
-	msg := l.getTime() + infoKey + l.tag + ": " + fmt.Sprintf(format, a...)
-	l.writeLog(msg)
-	msg = l.applyStyle(msg, l.infoStyle...)
-	log.Println(msg)
+	if l.enabled(LevelInfo) {
+		l.log(LevelInfo, fmt.Sprintf(format, a...), a)
+	}
 }
 
 func (l *LoggerSync) Warn(a ...any) {
-	msg := l.getTime() + warnKey + l.tag + ": " + fmt.Sprint(a...)
-	l.writeLog(msg)
-	msg = l.applyStyle(msg, l.warnStyle...)
-	log.Println(msg)
+	if l.enabled(LevelWarn) {
+		l.log(LevelWarn, message(a), a)
+	}
 }
 
 func (l *LoggerSync) Warnf(format string, a ...any) {
-	msg := l.getTime() + warnKey + l.tag + ": " + fmt.Sprintf(format, a...)
-	l.writeLog(msg)
-	msg = l.applyStyle(msg, l.warnStyle...)
-	log.Println(msg)
+	if l.enabled(LevelWarn) {
+		l.log(LevelWarn, fmt.Sprintf(format, a...), a)
+	}
 }
 
 func (l *LoggerSync) Error(a ...any) {
-	msg := l.getTime() + errorKey + l.tag + ": " + fmt.Sprint(a...)
-	l.writeLog(msg)
-	msg = l.applyStyle(msg, l.errorStyle...)
-	log.Println(msg)
+	if l.enabled(LevelError) {
+		l.log(LevelError, message(a), a)
+	}
 }
 
 func (l *LoggerSync) Errorf(format string, a ...any) {
-	msg := l.getTime() + errorKey + l.tag + ": " + fmt.Sprintf(format, a...)
-	l.writeLog(msg)
-	msg = l.applyStyle(msg, l.errorStyle...)
-	log.Println(msg)
+	if l.enabled(LevelError) {
+		l.log(LevelError, fmt.Sprintf(format, a...), a)
+	}
 }
 
 func (l *LoggerSync) Debug(a ...any) {
-	if l.enDebug {
-		msg := l.getTime() + debugKey + l.tag + ": " + fmt.Sprint(a...)
-		l.writeLog(msg)
-		msg = l.applyStyle(msg, l.debugStyle...)
-		log.Println(msg)
+	if l.enabled(LevelDebug) {
+		l.log(LevelDebug, message(a), a)
 	}
 }
 
 func (l *LoggerSync) Debugf(format string, a ...any) {
-	if l.enDebug {
-		msg := l.getTime() + debugKey + l.tag + ": " + fmt.Sprintf(format, a...)
-		l.writeLog(msg)
-		msg = l.applyStyle(msg, l.debugStyle...)
-		log.Println(msg)
+	if l.enabled(LevelDebug) {
+		l.log(LevelDebug, fmt.Sprintf(format, a...), a)
 	}
 }
 
 func (l *LoggerSync) Panic(a ...any) {
-	msg := l.getTime() + panicKey + l.tag + ": " + fmt.Sprint(a...)
-	l.writeLog(msg)
-	msg = l.applyStyle(msg, l.panicStyle...)
-	log.Panicln(msg)
+	l.log(LevelPanic, message(a), a)
 }
 
 func (l *LoggerSync) Panicf(format string, a ...any) {
-	msg := l.getTime() + panicKey + l.tag + ": " + fmt.Sprintf(format, a...)
-	l.writeLog(msg)
-	msg = l.applyStyle(msg, l.panicStyle...)
-	log.Panicln(msg)
+	l.log(LevelPanic, fmt.Sprintf(format, a...), a)
 }
 
 func (l *LoggerSync) Fatal(a ...any) {
-	msg := l.getTime() + fatalKey + l.tag + ": " + fmt.Sprint(a...)
-	l.writeLog(msg)
-	msg = l.applyStyle(msg, l.fatalStyle...)
-	log.Fatalln(msg)
+	l.log(LevelFatal, message(a), a)
 }
 
 func (l *LoggerSync) Fatalf(format string, a ...any) {
-	msg := l.getTime() + fatalKey + l.tag + ": " + fmt.Sprintf(format, a...)
-	l.writeLog(msg)
-	msg = l.applyStyle(msg, l.fatalStyle...)
-	log.Fatalln(msg)
+	l.log(LevelFatal, fmt.Sprintf(format, a...), a)
 }
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+)
+
+// tagPalette256 are colors of the 256 color palette that read well on dark
+// and light backgrounds, tagPalette16 is the fallback for basic terminals
+var (
+	tagPalette256 = [...]uint8{33, 37, 41, 69, 75, 99, 106, 112, 134, 136, 141, 166, 168, 172, 178, 202, 208, 214, 39, 170}
+	tagPalette16  = [...]uint8{1, 2, 3, 4, 5, 6, 9, 10, 11, 12, 13, 14}
+)
+
+// tagPins holds the pinned tag colors, gen counts the changes so loggers know
+// when to resolve their tag colors again
+var tagPins = struct {
+	sync.RWMutex
+	m   map[string]Color
+	gen atomic.Uint64
+}{m: map[string]Color{}}
+
+// PinTagColor makes every logger with the given tag use c for it when tag
+// colors are enabled, instead of the color picked from the tag name. Loggers
+// that already color their tag switch to c with their next line.
+// Example:
+// logger.PinTagColor("DB", logger.ColorMagenta)
+func PinTagColor(tag string, c Color) {
+	tagPins.Lock()
+	tagPins.m[tag] = c
+	tagPins.Unlock()
+	tagPins.gen.Add(1)
+}
+
+// TagColor returns the color of tag, the pinned one or one picked from a
+// hash of the name, so a tag gets the same color in every run and process
+func TagColor(tag string) Color {
+	return tagColor(tag, profileTrueColor)
+}
+
+// tagColor is TagColor for a terminal showing p colors, basic terminals pick
+// from their own palette so different tags stay apart
+func tagColor(tag string, p colorProfile) Color {
+	tagPins.RLock()
+	c, ok := tagPins.m[tag]
+	tagPins.RUnlock()
+	if ok {
+		return c
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(tag))
+	sum := h.Sum32()
+	if p == profile16 {
+		return ANSI(tagPalette16[sum%uint32(len(tagPalette16))])
+	}
+	return Color256(tagPalette256[sum%uint32(len(tagPalette256))])
+}
+
+// SetTagColors colors the bracketed tag of console lines with TagColor, so
+// the output of loggers with different tags is easy to tell apart. The
+// attributes of the theme tag style are kept.
+// Example:
+// logger.SetTagColors(true)
+func (l *core) SetTagColors(enable bool) {
+	l.updateStyles(func(set *styleSet) {
+		set.tagColorsOn = enable
+	})
+}
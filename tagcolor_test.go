@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTagColor_Stable(t *testing.T) {
+	seen := map[Color]bool{}
+	for _, tag := range []string{"GPIO", "MQTT", "DB", "HTTP", "CACHE", "AUTH"} {
+		c := TagColor(tag)
+		if c != TagColor(tag) {
+			t.Errorf("Expected %s to get the same color every time", tag)
+		}
+		if c.kind != color256 || !slices.Contains(tagPalette256[:], uint8(c.value)) {
+			t.Errorf("Expected %s to get a palette color but got %+v", tag, c)
+		}
+		if basic := tagColor(tag, profile16); basic.kind != colorANSI {
+			t.Errorf("Expected %s to get a basic color on basic terminals but got %+v", tag, basic)
+		}
+		seen[c] = true
+	}
+	if len(seen) < 3 {
+		t.Errorf("Expected different tags to get different colors but got %d colors", len(seen))
+	}
+}
+
+func TestLoggerSync_SetTagColors(t *testing.T) {
+	t.Setenv("COLORTERM", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	buf := &testBuffer{}
+	logger := New(Options{Tag: "MQTT", Color: ColorAlways, TagColors: true})
+	logger.SetOutput(buf)
+	logger.UseTheme("monochrome")
+
+	logger.Info("connected")
+
+	want := fmt.Sprintf("\033[1;38;5;%dm[MQTT   ]\033[0m", TagColor("MQTT").value)
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected the tag in %q with the theme attributes but got: %q", want, buf.String())
+	}
+}
+
+func TestLoggerSync_PinTagColor(t *testing.T) {
+	t.Setenv("COLORTERM", "truecolor")
+	PinTagColor("PINNED", RGB(1, 2, 3))
+
+	buf := &testBuffer{}
+	logger := NewSync("PINNED", false)
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
+	logger.SetTagColors(true)
+
+	logger.Info("pinned")
+
+	if !strings.Contains(buf.String(), "\033[38;2;1;2;3m[PINNED ]\033[0m") {
+		t.Errorf("Expected the pinned tag color but got: %q", buf.String())
+	}
+
+	buf = &testBuffer{}
+	logger.SetOutput(buf)
+	logger.SetTagColors(false)
+	logger.Info("plain")
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("Expected no tag color once turned off but got: %q", buf.String())
+	}
+}
+
+func TestLoggerSync_PinTagColor_AfterEnable(t *testing.T) {
+	t.Setenv("COLORTERM", "truecolor")
+
+	buf := &testBuffer{}
+	logger := New(Options{Tag: "LATEPIN", Color: ColorAlways, TagColors: true})
+	logger.SetOutput(buf)
+
+	logger.Info("hashed")
+	PinTagColor("LATEPIN", RGB(4, 5, 6))
+	logger.Info("pinned")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || strings.Contains(lines[0], "38;2;4;5;6") {
+		t.Fatalf("Expected the hashed color before pinning but got: %q", buf.String())
+	}
+	if !strings.Contains(lines[1], "\033[38;2;4;5;6m[LATEPIN]\033[0m") {
+		t.Errorf("Expected the color pinned after enabling but got: %q", lines[1])
+	}
+}
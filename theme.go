@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ThemeEnv is the environment variable selecting the theme at startup, it
+// holds the name of a registered theme or the path of a JSON theme file
+const ThemeEnv = "LOGGER_THEME"
+
+// Theme is a named set of console styles for every level and for the
+// timestamp, the tag and the field keys of text lines. Levels without a
+// style and zero styles are written unstyled.
+//
+// Theme files are JSON:
+//
+//	{
+//		"name": "ocean",
+//		"levels": {"info": {"fg": "cyan"}, "error": {"fg": "#ff5555", "attrs": ["bold"]}},
+//		"time": {"attrs": ["faint"]},
+//		"tag": {"fg": "blue"},
+//		"fieldKey": {"fg": "bright-black"}
+//	}
+type Theme struct {
+	Name     string          `json:"name"`
+	Levels   map[Level]Style `json:"levels"`
+	Time     Style           `json:"time"`
+	Tag      Style           `json:"tag"`
+	FieldKey Style           `json:"fieldKey"`
+}
+
+var themes = struct {
+	sync.RWMutex
+	m map[string]Theme
+}{m: map[string]Theme{}}
+
+func init() {
+	for _, t := range builtinThemes() {
+		RegisterTheme(t)
+	}
+}
+
+// RegisterTheme makes t available to UseTheme and LOGGER_THEME under its
+// name, matched case insensitively. It replaces a theme with the same name,
+// the built in ones included.
+func RegisterTheme(t Theme) {
+	t.Levels = maps.Clone(t.Levels)
+	themes.Lock()
+	themes.m[strings.ToLower(t.Name)] = t
+	themes.Unlock()
+}
+
+// LookupTheme returns the registered theme called name
+func LookupTheme(name string) (Theme, bool) {
+	themes.RLock()
+	t, ok := themes.m[strings.ToLower(name)]
+	themes.RUnlock()
+	t.Levels = maps.Clone(t.Levels)
+	return t, ok
+}
+
+// LoadTheme reads a JSON theme file, a theme without a name is named after
+// the file. The theme is not registered, see RegisterTheme.
+// Example:
+// t, err := logger.LoadTheme("themes/ocean.json")
+func LoadTheme(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+	var t Theme
+	if err := json.Unmarshal(data, &t); err != nil {
+		return Theme{}, fmt.Errorf("theme %s: %w", path, err)
+	}
+	if t.Name == "" {
+		t.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return t, nil
+}
+
+// themeFromEnv returns the theme selected with LOGGER_THEME, a value that is
+// neither a registered theme nor a readable theme file is ignored
+func themeFromEnv() (Theme, bool) {
+	name := os.Getenv(ThemeEnv)
+	if name == "" {
+		return Theme{}, false
+	}
+	if t, ok := LookupTheme(name); ok {
+		return t, true
+	}
+	t, err := LoadTheme(name)
+	return t, err == nil
+}
+
+// SetTheme replaces every console style with the ones of t in one step,
+// SetTagColors stays in effect
+func (l *core) SetTheme(t Theme) {
+	l.updateStyles(func(set *styleSet) {
+		*set = styleSet{tagColorsOn: set.tagColorsOn}
+		for lv, s := range t.Levels {
+			if lv >= LevelTrace && lv < LevelOff {
+				set.levels[lv] = s
+			}
+		}
+		set.parts[partTime] = t.Time
+		set.parts[partTag] = t.Tag
+		set.parts[partKey] = t.FieldKey
+	})
+}
+
+// UseTheme applies the registered theme called name
+// Example:
+// err := logger.UseTheme("solarized")
+func (l *core) UseTheme(name string) error {
+	t, ok := LookupTheme(name)
+	if !ok {
+		return fmt.Errorf("unknown theme %q", name)
+	}
+	l.SetTheme(t)
+	return nil
+}
+
+func builtinThemes() []Theme {
+	return []Theme{
+		{
+			Name: "default",
+			Levels: map[Level]Style{
+				LevelTrace: NewStyle(AttrFaint),
+				LevelDebug: NewStyle(AttrItalic, AttrFaint),
+				LevelInfo:  NewStyle().Foreground(ColorWhite),
+				LevelWarn:  NewStyle().Foreground(ColorYellow),
+				LevelError: NewStyle().Foreground(ColorRed),
+				LevelPanic: NewStyle(AttrBold).Foreground(ColorBlack).Background(ColorMagenta),
+				LevelFatal: NewStyle(AttrBold).Foreground(ColorBlack).Background(ColorRed),
+			},
+		},
+		{
+			// https://ethanschoonover.com/solarized/
+			Name: "solarized",
+			Levels: map[Level]Style{
+				LevelTrace: NewStyle().Foreground(RGB(0x58, 0x6e, 0x75)),
+				LevelDebug: NewStyle(AttrItalic).Foreground(RGB(0x93, 0xa1, 0xa1)),
+				LevelInfo:  NewStyle().Foreground(RGB(0x83, 0x94, 0x96)),
+				LevelWarn:  NewStyle().Foreground(RGB(0xb5, 0x89, 0x00)),
+				LevelError: NewStyle().Foreground(RGB(0xdc, 0x32, 0x2f)),
+				LevelPanic: NewStyle(AttrBold).Foreground(RGB(0xfd, 0xf6, 0xe3)).Background(RGB(0xd3, 0x36, 0x82)),
+				LevelFatal: NewStyle(AttrBold).Foreground(RGB(0xfd, 0xf6, 0xe3)).Background(RGB(0xdc, 0x32, 0x2f)),
+			},
+			Time:     NewStyle().Foreground(RGB(0x58, 0x6e, 0x75)),
+			Tag:      NewStyle().Foreground(RGB(0x6c, 0x71, 0xc4)),
+			FieldKey: NewStyle().Foreground(RGB(0x2a, 0xa1, 0x98)),
+		},
+		{
+			Name: "high-contrast",
+			Levels: map[Level]Style{
+				LevelTrace: NewStyle().Foreground(ColorWhite),
+				LevelDebug: NewStyle().Foreground(ANSI(14)),
+				LevelInfo:  NewStyle().Foreground(ANSI(15)),
+				LevelWarn:  NewStyle(AttrBold).Foreground(ANSI(11)),
+				LevelError: NewStyle(AttrBold).Foreground(ANSI(9)),
+				LevelPanic: NewStyle(AttrBold).Foreground(ColorBlack).Background(ANSI(13)),
+				LevelFatal: NewStyle(AttrBold).Foreground(ANSI(15)).Background(ColorRed),
+			},
+			Time:     NewStyle().Foreground(ANSI(15)),
+			Tag:      NewStyle(AttrBold).Foreground(ANSI(15)),
+			FieldKey: NewStyle(AttrBold).Foreground(ANSI(14)),
+		},
+		{
+			Name: "monochrome",
+			Levels: map[Level]Style{
+				LevelTrace: NewStyle(AttrFaint),
+				LevelDebug: NewStyle(AttrItalic),
+				LevelWarn:  NewStyle(AttrBold),
+				LevelError: NewStyle(AttrBold, AttrUnderline),
+				LevelPanic: NewStyle(AttrBold, AttrReverse),
+				LevelFatal: NewStyle(AttrBold, AttrReverse),
+			},
+			Time:     NewStyle(AttrFaint),
+			Tag:      NewStyle(AttrBold),
+			FieldKey: NewStyle(AttrUnderline),
+		},
+	}
+}
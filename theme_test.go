@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const oceanTheme = `{
+	"levels": {"info": {"fg": "cyan"}, "error": {"fg": "#ff5555", "attrs": ["bold"]}},
+	"time": {"attrs": ["faint"]},
+	"tag": {"fg": "bright-blue"},
+	"fieldKey": {"fg": "208"}
+}`
+
+func writeTheme(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ocean.json")
+	if err := os.WriteFile(path, []byte(oceanTheme), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadTheme(t *testing.T) {
+	theme, err := LoadTheme(writeTheme(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if theme.Name != "ocean" {
+		t.Errorf("Expected the theme to be named after the file but got %q", theme.Name)
+	}
+	if got, want := theme.Levels[LevelError], NewStyle(AttrBold).Foreground(RGB(0xff, 0x55, 0x55)); got != want {
+		t.Errorf("Expected %+v but got %+v", want, got)
+	}
+	if got, want := theme.Tag, NewStyle().Foreground(ANSI(12)); got != want {
+		t.Errorf("Expected %+v but got %+v", want, got)
+	}
+	if got, want := theme.FieldKey, NewStyle().Foreground(Color256(208)); got != want {
+		t.Errorf("Expected %+v but got %+v", want, got)
+	}
+}
+
+func TestStyle_JSON_RoundTrip(t *testing.T) {
+	for _, s := range []Style{
+		{},
+		NewStyle(AttrBlink, AttrStrikethrough),
+		NewStyle().Foreground(ANSI(9)).Background(ColorDefault),
+		NewStyle(AttrReverse).Foreground(RGB(1, 2, 3)).Background(Color256(42)),
+	} {
+		data, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Style
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != s {
+			t.Errorf("Expected %+v after a round trip through %s but got %+v", s, data, got)
+		}
+	}
+}
+
+func TestStyle_UnmarshalJSON_Errors(t *testing.T) {
+	for _, data := range []string{`{"fg": "purple"}`, `{"bg": "#12345"}`, `{"attrs": ["shiny"]}`} {
+		var s Style
+		if err := json.Unmarshal([]byte(data), &s); err == nil {
+			t.Errorf("Expected an error for %s", data)
+		}
+	}
+}
+
+func TestLoggerSync_ThemeFromEnv(t *testing.T) {
+	t.Setenv(ThemeEnv, "Monochrome")
+
+	buf := &testBuffer{}
+	logger := NewSync("TEST", false)
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
+
+	logger.With("pin", 17).Warn("hot")
+
+	want := "\033[1m[\033[2m" + "TIME" + "\033[0m\033[1m] [WARN ] \033[1m[TEST   ]\033[0m\033[1m: hot \033[4mpin\033[0m\033[1m=17\033[0m\n"
+	got := buf.String()
+	// Replace the timestamp, the only part that changes between runs
+	if start, end := strings.Index(got, "\033[2m")+4, strings.Index(got, "\033[0m"); start > 3 && end > start {
+		got = got[:start] + "TIME" + got[end:]
+	}
+	if got != want {
+		t.Errorf("Expected %q but got %q", want, got)
+	}
+}
+
+func TestLoggerSync_ThemeFile_SetDefaultStyle(t *testing.T) {
+	t.Setenv(ThemeEnv, writeTheme(t))
+
+	dir := t.TempDir()
+	buf := &testBuffer{}
+	logger := NewSync("TEST", false)
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
+	logger.SetWriteFilesEnable(dir, "OBJ")
+	logger.SetDefaultStyle()
+
+	logger.Info("themed")
+	logger.Close(t.Context())
+
+	if !strings.HasPrefix(buf.String(), "\033[36m[") {
+		t.Errorf("Expected SetDefaultStyle to keep the theme from the environment but got: %q", buf.String())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, fileNameGenerator("OBJ")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "\033[") {
+		t.Errorf("Expected a plain log file but got: %q", data)
+	}
+}
+
+func TestLoggerSync_UseTheme(t *testing.T) {
+	buf := &testBuffer{}
+	logger := NewSync("TEST", false)
+	logger.SetOutput(buf)
+	logger.SetColorMode(ColorAlways)
+
+	if err := logger.UseTheme("no-such-theme"); err == nil {
+		t.Error("Expected an error for an unknown theme")
+	}
+
+	RegisterTheme(Theme{Name: "test-only", Tag: NewStyle().Foreground(ColorGreen)})
+	if err := logger.UseTheme("test-only"); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.SetConsoleLayout("{tag} {msg}"); err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("registered")
+
+	if got, want := buf.String(), "\033[32mTEST\033[0m registered\n"; got != want {
+		t.Errorf("Expected %q but got %q", want, got)
+	}
+}
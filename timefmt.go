@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	// TimeFormatRFC3339Nano writes timestamps like 2006-01-02T15:04:05.999999999Z07:00
+	TimeFormatRFC3339Nano = time.RFC3339Nano
+	// TimeFormatUnixMilli writes timestamps as milliseconds since the Unix
+	// epoch, structured encoders write them as a number
+	TimeFormatUnixMilli = "unixms"
+
+	textTimeLayout       = "2006-01-02 15:04:05.000"
+	structuredTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// timeFormat is the timestamp layout and location of log lines, it is
+// replaced as a whole so newEntry reads both with a single atomic load
+type timeFormat struct {
+	layout string
+	loc    *time.Location
+}
+
+// SetTimeFormat selects the timestamp layout of every encoder, it takes a
+// time package layout, TimeFormatRFC3339Nano or TimeFormatUnixMilli. An empty
+// layout restores the default of each encoder.
+// Example:
+// logger.SetTimeFormat(logger.TimeFormatRFC3339Nano)
+func (l *core) SetTimeFormat(layout string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	tf := *l.timeFormat.Load()
+	tf.layout = layout
+	l.timeFormat.Store(&tf)
+}
+
+// SetTimeLocation selects the time zone of the timestamps in log lines, nil
+// restores local time. File names and rotation use SetFileLocation.
+// Example:
+// logger.SetTimeLocation(time.UTC)
+func (l *core) SetTimeLocation(loc *time.Location) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	tf := *l.timeFormat.Load()
+	tf.loc = loc
+	l.timeFormat.Store(&tf)
+}
+
+// SetFileLocation selects the time zone of the date in log file names and of
+// the hour and minute given to ChangeFileRoutine, nil restores local time.
+// It applies from the next file opened, so call it before SetWriteFilesEnable.
+func (l *core) SetFileLocation(loc *time.Location) {
+	l.file.mu.Lock()
+	l.file.loc = loc
+	l.file.mu.Unlock()
+}
+
+// SetUTC writes log lines, names log files and rotates them in UTC
+func (l *core) SetUTC() {
+	l.SetTimeLocation(time.UTC)
+	l.SetFileLocation(time.UTC)
+}
+
+// appendTime appends e.Time in the layout set with SetTimeFormat, or in
+// layout when none was set
+func appendTime(dst []byte, e *Entry, layout string) []byte {
+	if e.Time.IsZero() {
+		return dst
+	}
+	switch e.timeLayout {
+	case "":
+	case TimeFormatUnixMilli:
+		return strconv.AppendInt(dst, e.Time.UnixMilli(), 10)
+	default:
+		layout = e.timeLayout
+	}
+	if layout == textTimeLayout {
+		return appendTimestamp(dst, e.Time)
+	}
+	return e.Time.AppendFormat(dst, layout)
+}
+
+// appendTimestamp appends t as 2006-01-02 15:04:05.000, it is the hot path
+// equivalent of t.AppendFormat with that layout
+func appendTimestamp(dst []byte, t time.Time) []byte {
+	year, month, day := t.Date()
+	hour, minute, sec := t.Clock()
+	dst = appendInt(dst, year, 4)
+	dst = append(dst, '-')
+	dst = appendInt(dst, int(month), 2)
+	dst = append(dst, '-')
+	dst = appendInt(dst, day, 2)
+	dst = append(dst, ' ')
+	dst = appendInt(dst, hour, 2)
+	dst = append(dst, ':')
+	dst = appendInt(dst, minute, 2)
+	dst = append(dst, ':')
+	dst = appendInt(dst, sec, 2)
+	dst = append(dst, '.')
+	return appendInt(dst, t.Nanosecond()/int(time.Millisecond), 3)
+}
+
+// appendInt appends the decimal form of a non-negative n zero padded to width
+func appendInt(dst []byte, n int, width int) []byte {
+	var digits [20]byte
+	i := len(digits)
+	for n >= 10 || width > 1 {
+		i--
+		digits[i] = byte('0' + n%10)
+		n /= 10
+		width--
+	}
+	i--
+	digits[i] = byte('0' + n)
+	return append(dst, digits[i:]...)
+}
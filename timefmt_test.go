@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoggerSync_SetTimeFormat_UnixMilli(t *testing.T) {
+	buf := &testBuffer{}
+	logger := NewSync("TEST", false)
+	logger.SetOutput(buf)
+	logger.SetTimeFormat(TimeFormatUnixMilli)
+
+	logger.Info("epoch")
+
+	if !regexp.MustCompile(`^\[\d{13}\] \[INFO \]`).MatchString(buf.String()) {
+		t.Errorf("Expected a millisecond timestamp but got: %q", buf.String())
+	}
+}
+
+func TestLoggerSync_SetTimeLocation(t *testing.T) {
+	buf := &testBuffer{}
+	logger := NewSync("TEST", false)
+	logger.SetOutput(buf)
+	logger.SetConsoleEncoder(NewLogfmtEncoder())
+	logger.SetTimeFormat(TimeFormatRFC3339Nano)
+	logger.SetTimeLocation(time.UTC)
+
+	logger.Info("utc")
+
+	got := parseLogfmt(t, strings.TrimSpace(buf.String()))
+	ts, err := time.Parse(time.RFC3339Nano, got["time"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(got["time"], "Z") || time.Since(ts) > time.Minute {
+		t.Errorf("Expected the current time in UTC but got: %q", got["time"])
+	}
+}
+
+func TestJSONEncoder_TimeFormat(t *testing.T) {
+	e := &Entry{Time: time.Date(2025, 5, 23, 10, 4, 5, 0, time.UTC), Level: LevelInfo}
+
+	e.timeLayout = TimeFormatUnixMilli
+	var got map[string]any
+	if err := json.Unmarshal(NewJSONEncoder().Encode(nil, e), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["time"] != float64(e.Time.UnixMilli()) {
+		t.Errorf("Expected a numeric timestamp but got: %v", got["time"])
+	}
+
+	e.timeLayout = "Jan _2 15:04:05"
+	if err := json.Unmarshal(NewJSONEncoder().Encode(nil, e), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["time"] != "May 23 10:04:05" {
+		t.Errorf("Expected the custom layout but got: %v", got["time"])
+	}
+}
+
+func TestLoggerSync_SetFileLocation(t *testing.T) {
+	dir := t.TempDir()
+	// UTC+14 and UTC-12 are 26 hours apart, so they never share a date
+	east, west := time.FixedZone("EAST", 14*3600), time.FixedZone("WEST", -12*3600)
+
+	for _, loc := range []*time.Location{east, west} {
+		logger := NewSync("TEST", false)
+		logger.SetOutput(&testBuffer{})
+		logger.SetFileLocation(loc)
+		logger.SetWriteFilesEnable(dir, loc.String())
+		logger.Info("located")
+		logger.Close(t.Context())
+
+		name := fileNameAt(loc.String(), time.Now().In(loc))
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to exist: %v", name, err)
+		}
+	}
+}
@@ -32,8 +32,13 @@ func createAndAppendObject(fileName string, path string) *os.File {
 }
 
 func fileNameGenerator(objectName string) string {
+	return fileNameAt(objectName, time.Now())
+}
+
+// fileNameAt returns the name of the log file of objectName for the day of t
+func fileNameAt(objectName string, t time.Time) string {
 	// Generate file name based on gateName
-	today := time.Now().Format("2006-01-02")
+	today := t.Format("2006-01-02")
 	return fmt.Sprintf("%s:%s.txt", today, objectName)
 }
 
@@ -46,3 +51,13 @@ func newFolderPath(path string) string {
 	}
 	return path
 }
+
+// appendTag appends the tag padded to 7 characters and wrapped in brackets
+func appendTag(dst []byte, tag string) []byte {
+	dst = append(dst, '[')
+	dst = append(dst, tag...)
+	for i := len(tag); i < 7; i++ {
+		dst = append(dst, ' ')
+	}
+	return append(dst, ']')
+}